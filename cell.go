@@ -1,9 +1,12 @@
 package mdtt
 
 import (
-	"github.com/charmbracelet/bubbles/textinput"
+	"regexp"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/mattn/go-runewidth"
+	"github.com/charmbracelet/lipgloss"
 )
 
 type (
@@ -15,24 +18,28 @@ type widthMsg struct {
 }
 
 type cell struct {
-	textInput textinput.Model
-	err       error
+	// text is the markdown of the cell, in which <br> breaks the lines.
+	text string
+	// editor holds the text as lines while the cell is edited in insert
+	// mode.
+	editor  textarea.Model
+	editing bool
+	err     error
 }
 
 func NewCell(value string) cell {
-	ta := textinput.New()
-	ta.Placeholder = ""
-	ta.Focus()
-	ta.SetValue(value)
-	ta.Prompt = ""
-	ta.Cursor.Style = cellCursorStyle
-	return cell{textInput: ta, err: nil}
+	return cell{text: value}
 }
 
 var (
 	widthPadding = 2
+	// maxAddedLines is the number of lines a paste can add to a cell at once
+	maxAddedLines = 100
 )
 
+// lineBreak matches the HTML line breaks of a cell.
+var lineBreak = regexp.MustCompile(`(?i)<br\s*/?>`)
+
 func (m cell) update(msg tea.Msg) (cell, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -42,8 +49,15 @@ func (m cell) update(msg tea.Msg) (cell, tea.Cmd) {
 		return m, nil
 	}
 
-	m.textInput, cmd = m.textInput.Update(msg)
-	width := runewidth.StringWidth(m.textInput.Value()) + widthPadding
+	if m.editing {
+		// the editor is tall enough for the lines a key can add, so that it
+		// does not scroll before it is fitted to them
+		m.editor.SetHeight(m.editor.LineCount() + maxAddedLines)
+		m.editor, cmd = m.editor.Update(msg)
+		m.text = joinLines(m.editor.Value())
+		m.fitEditor()
+	}
+	width := cellWidth(m.text) + widthPadding
 	return m, tea.Batch(cmd, updateWidthCmd(width))
 }
 
@@ -53,18 +67,87 @@ func updateWidthCmd(width int) tea.Cmd {
 	}
 }
 
+// startEdit puts the lines of the cell in its editor, with the cursor at the
+// end.
+func (m *cell) startEdit() {
+	plain := lipgloss.NewStyle()
+	style := textarea.Style{
+		Base:             plain,
+		CursorLine:       plain,
+		CursorLineNumber: plain,
+		EndOfBuffer:      plain,
+		LineNumber:       plain,
+		Placeholder:      plain,
+		Prompt:           plain,
+		Text:             plain,
+	}
+
+	ta := textarea.New()
+	ta.Prompt = ""
+	ta.ShowLineNumbers = false
+	ta.CharLimit = 0
+	ta.MaxWidth = 0
+	ta.MaxHeight = 0
+	ta.FocusedStyle = style
+	ta.BlurredStyle = style
+	ta.Cursor.Style = cellCursorStyle
+	ta.Focus()
+	ta.SetValue(strings.Join(cellLines(m.text), "\n"))
+
+	m.editor = ta
+	m.editing = true
+	m.fitEditor()
+}
+
+// stopEdit drops the editor of the cell.
+func (m *cell) stopEdit() {
+	m.editor = textarea.Model{}
+	m.editing = false
+}
+
+// fitEditor sizes the editor to the lines of the cell, leaving room for the
+// cursor at the end of the longest one.
+func (m *cell) fitEditor() {
+	m.editor.SetWidth(cellWidth(m.text) + 1)
+	m.editor.SetHeight(m.editor.LineCount())
+}
+
 func (m cell) view() string {
-	return m.textInput.View()
+	if m.editing {
+		return m.editor.View()
+	}
+	return strings.Join(cellLines(m.text), "\n")
 }
 
 func (m cell) value() string {
-	return m.textInput.Value()
+	return m.text
 }
 
 func (m *cell) setValue(s string) {
-	m.textInput.SetValue(s)
+	m.text = s
+	if m.editing {
+		m.editor.SetValue(strings.Join(cellLines(s), "\n"))
+		m.fitEditor()
+	}
+}
+
+// cellLines splits the text of a cell into the lines shown in the table,
+// breaking it at <br> and line breaks.
+func cellLines(s string) []string {
+	return strings.Split(lineBreak.ReplaceAllString(s, "\n"), "\n")
 }
 
-func (m *cell) setCursor(l int) {
-	m.textInput.SetCursor(l)
+// joinLines joins the lines of a cell with <br>, since a markdown table
+// cell cannot hold a line break.
+func joinLines(s string) string {
+	return strings.ReplaceAll(s, "\n", "<br>")
+}
+
+// cellWidth returns the width of the widest line of a cell.
+func cellWidth(s string) int {
+	var w int
+	for _, l := range cellLines(s) {
+		w = max(displayWidth(l), w)
+	}
+	return w
 }
@@ -0,0 +1,69 @@
+package mdtt
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCellLines(t *testing.T) {
+	testCases := []struct {
+		in        string
+		wantLines []string
+		wantWidth int
+	}{
+		{"abc", []string{"abc"}, 3},
+		{"a<br>bcd", []string{"a", "bcd"}, 3},
+		{"a<br/>b<BR />c", []string{"a", "b", "c"}, 1},
+		{"a\nbc", []string{"a", "bc"}, 2},
+		{"<b>bold</b>", []string{"<b>bold</b>"}, 11},
+	}
+
+	for _, tc := range testCases {
+		if diff := cmp.Diff(tc.wantLines, cellLines(tc.in)); diff != "" {
+			t.Errorf("%q lines differ: (-want +got)\n%s", tc.in, diff)
+		}
+		if got := cellWidth(tc.in); got != tc.wantWidth {
+			t.Errorf("%q width: want %d, got %d", tc.in, tc.wantWidth, got)
+		}
+	}
+}
+
+func TestMultiLineEdit(t *testing.T) {
+	testCases := []struct {
+		name string
+		keys []tea.KeyMsg
+		want string
+	}{
+		{
+			name: "new line",
+			keys: append(append(keys("i"), tea.KeyMsg{Type: tea.KeyEnter}), keys("c")...),
+			want: "a<br>b<br>c",
+		},
+		{
+			name: "join lines",
+			keys: append(keys("i"), tea.KeyMsg{Type: tea.KeyCtrlA}, tea.KeyMsg{Type: tea.KeyBackspace}),
+			want: "ab",
+		},
+		{
+			name: "unedited",
+			keys: keys("jk"),
+			want: "a<br/>b",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parse([]byte("| h |\n| - |\n| a<br/>b |\n| c |\n"))[0]
+			for _, k := range tc.keys {
+				m, _ = m.Update(k)
+			}
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+			if diff := cmp.Diff(tc.want, m.rows[0][0].value()); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
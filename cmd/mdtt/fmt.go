@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/szktkfm/mdtt"
+)
+
+var fmtCmd = &cobra.Command{
+	Use:   "fmt [file...]",
+	Short: "Reformat the tables of markdown files",
+	Long: `Reformat every table of the markdown files with consistent column padding,
+without launching the TUI. The formatted files are printed unless -i, --check
+or --diff is given. Without files, the standard input is formatted.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		check, _ := cmd.Flags().GetBool("check")
+		diff, _ := cmd.Flags().GetBool("diff")
+		inplace, _ := cmd.Flags().GetBool("inplace")
+		if inplace && len(args) == 0 {
+			fmt.Fprintln(os.Stderr, "no input files")
+			os.Exit(2)
+		}
+
+		f := formatter{
+			check:   check,
+			diff:    diff,
+			inplace: inplace,
+			print:   !check && !diff && !inplace,
+		}
+		if len(args) == 0 {
+			f.formatStdin()
+		}
+		for _, path := range args {
+			f.formatFile(path)
+		}
+		os.Exit(f.status)
+	},
+}
+
+// formatter formats files for the fmt command and keeps its exit status: 1
+// when --check finds a file to reformat, 2 on errors.
+type formatter struct {
+	check   bool
+	diff    bool
+	inplace bool
+	print   bool
+	status  int
+}
+
+func (f *formatter) formatStdin() {
+	src, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		f.fail("<standard input>", err)
+		return
+	}
+	out, ok := f.format("<standard input>", src)
+	if ok && f.print {
+		os.Stdout.Write(out)
+	}
+}
+
+func (f *formatter) formatFile(path string) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		f.fail(path, err)
+		return
+	}
+	out, ok := f.format(path, src)
+	if !ok {
+		return
+	}
+	if f.print {
+		os.Stdout.Write(out)
+	}
+	if f.inplace && !bytes.Equal(src, out) {
+		info, err := os.Stat(path)
+		if err != nil {
+			f.fail(path, err)
+			return
+		}
+		if err := os.WriteFile(path, out, info.Mode().Perm()); err != nil {
+			f.fail(path, err)
+		}
+	}
+}
+
+// format formats src and reports it for --check and --diff.
+func (f *formatter) format(name string, src []byte) ([]byte, bool) {
+	out, err := mdtt.Format(src)
+	if err != nil {
+		f.fail(name, err)
+		return nil, false
+	}
+	if bytes.Equal(src, out) {
+		return out, true
+	}
+	if f.check {
+		fmt.Println(name)
+		f.status = max(f.status, 1)
+	}
+	if f.diff {
+		fmt.Print(mdtt.UnifiedDiff(name, src, out))
+	}
+	return out, true
+}
+
+func (f *formatter) fail(name string, err error) {
+	fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+	f.status = 2
+}
+
+func init() {
+	fmtCmd.Flags().Bool(
+		"check",
+		false,
+		"list the files that would be reformatted and exit with status 1 if there are any",
+	)
+	fmtCmd.Flags().Bool(
+		"diff",
+		false,
+		"print the changes as a unified diff",
+	)
+	fmtCmd.Flags().BoolP(
+		"inplace",
+		"i",
+		false,
+		"write the formatted files in place",
+	)
+	rootCmd.AddCommand(fmtCmd)
+}
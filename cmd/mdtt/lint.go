@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/szktkfm/mdtt"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint [file...]",
+	Short: "Report malformed tables of markdown files",
+	Long: `Report the problems of the tables of the markdown files as file:line:column:
+rows with more or fewer cells than the header, unescaped pipes in code spans,
+delimiter rows that do not match the header, empty and duplicated column
+names, and trailing whitespace. Without files, the standard input is checked.
+The exit status is 1 when there are problems and 2 on errors.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		var status int
+		lint := func(name string, src []byte) {
+			for _, p := range mdtt.Lint(src) {
+				fmt.Printf("%s:%s\n", name, p)
+				status = max(status, 1)
+			}
+		}
+
+		if len(args) == 0 {
+			src, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "<standard input>: %v\n", err)
+				os.Exit(2)
+			}
+			lint("<standard input>", src)
+		}
+		for _, path := range args {
+			src, err := os.ReadFile(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s: %v\n", path, err)
+				status = 2
+				continue
+			}
+			lint(path, src)
+		}
+		os.Exit(status)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
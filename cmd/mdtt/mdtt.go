@@ -1,8 +1,12 @@
 package main
 
 import (
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
 	"os"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -22,7 +26,20 @@ var (
 	rootCmd = &cobra.Command{
 		Use:     "mdtt [file]",
 		Short:   "Markdown Table Editor with TUI",
+		Args:    cobra.ArbitraryArgs,
 		Version: "",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			switch ambiwidth, _ := cmd.Flags().GetString("ambiwidth"); ambiwidth {
+			case "":
+			case "single":
+				mdtt.SetAmbiguousWidth(false)
+			case "double":
+				mdtt.SetAmbiguousWidth(true)
+			default:
+				return fmt.Errorf("invalid ambiwidth: %s", ambiwidth)
+			}
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			debug, err := cmd.Flags().GetBool("debug")
 			if err != nil {
@@ -38,7 +55,22 @@ var (
 			if inplace && len(args) == 0 {
 				log.Fatal("no input files")
 			}
-			var model = createModel(args, inplace)
+			in, err := inputOptions(cmd)
+			if err != nil {
+				log.Fatal(err)
+			}
+			to, _ := cmd.Flags().GetString("to")
+			if inplace && to != mdtt.FormatMarkdown {
+				log.Fatal("in-place editing only writes markdown, use --to without -i")
+			}
+			config, err := loadConfig(cmd)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if err := setTheme(cmd, config); err != nil {
+				log.Fatal(err)
+			}
+			var model = createModel(args, inplace, in, mdtt.WithOutputFormat(to), mdtt.WithConfig(config))
 
 			p := tea.NewProgram(
 				model,
@@ -54,14 +86,109 @@ var (
 	}
 )
 
-func createModel(args []string, inplace bool) mdtt.Model {
+// input holds the flags that control how the input is read.
+type input struct {
+	from string
+	csv  mdtt.CSVOptions
+}
+
+func inputOptions(cmd *cobra.Command) (input, error) {
+	var in input
+	in.from, _ = cmd.Flags().GetString("from")
+	switch in.from {
+	case "auto", mdtt.FormatMarkdown, mdtt.FormatCSV, mdtt.FormatTSV:
+	case "md":
+		in.from = mdtt.FormatMarkdown
+	default:
+		return in, fmt.Errorf("invalid input format: %s", in.from)
+	}
+
+	delim, _ := cmd.Flags().GetString("delimiter")
+	d, err := mdtt.ParseDelimiter(delim)
+	if err != nil {
+		return in, err
+	}
+	in.csv.Delimiter = d
+
+	header, _ := cmd.Flags().GetString("header")
+	switch header {
+	case "auto":
+		in.csv.Header = mdtt.CSVHeaderAuto
+	case "yes":
+		in.csv.Header = mdtt.CSVHeaderYes
+	case "no":
+		in.csv.Header = mdtt.CSVHeaderNo
+	default:
+		return in, fmt.Errorf("invalid header option: %s", header)
+	}
+
+	in.csv.LazyQuotes, _ = cmd.Flags().GetBool("lazy-quotes")
+	return in, nil
+}
+
+// tableOption returns the option that reads content in the format given by
+// in, detecting it from path and content for "auto".
+func tableOption(content []byte, path string, in input) (mdtt.Option, string) {
+	format := in.from
+	if format == "auto" {
+		format = mdtt.DetectFormat(path, content)
+	}
+
+	switch format {
+	case mdtt.FormatTSV:
+		opts := in.csv
+		if opts.Delimiter == 0 {
+			opts.Delimiter = '\t'
+		}
+		return mdtt.WithCSV(content, opts), format
+	case mdtt.FormatCSV:
+		return mdtt.WithCSV(content, in.csv), format
+	}
+	return mdtt.WithMarkdown(content), format
+}
+
+// loadConfig reads the config file given with --config, or the default one
+// when it exists.
+func loadConfig(cmd *cobra.Command) (mdtt.Config, error) {
+	path, _ := cmd.Flags().GetString("config")
+	if path != "" {
+		return mdtt.LoadConfig(path)
+	}
+
+	path = mdtt.DefaultConfigPath()
+	if _, err := os.Stat(path); path == "" || errors.Is(err, fs.ErrNotExist) {
+		return mdtt.Config{}, nil
+	}
+	return mdtt.LoadConfig(path)
+}
+
+// setTheme sets the theme given with --theme or in the config, with the
+// colors the terminal supports. The TUI is drawn on stderr, so stdout may be
+// piped.
+func setTheme(cmd *cobra.Command, config mdtt.Config) error {
+	out := termenv.NewOutput(os.Stderr)
+	lipgloss.SetColorProfile(out.EnvColorProfile())
+
+	name, _ := cmd.Flags().GetString("theme")
+	theme, err := config.Theme(name, func() bool {
+		dark := out.HasDarkBackground()
+		lipgloss.SetHasDarkBackground(dark)
+		return dark
+	}, out.EnvNoColor())
+	if err != nil {
+		return err
+	}
+	mdtt.SetTheme(theme)
+	return nil
+}
+
+func createModel(args []string, inplace bool, in input, opts ...mdtt.Option) mdtt.Model {
 
 	if !isatty.IsTerminal(os.Stdin.Fd()) {
 
 		content, _ := io.ReadAll(os.Stdin)
-		model, err := mdtt.NewUI(
-			mdtt.WithMarkdown(content),
-		)
+		opt, _ := tableOption(content, "", in)
+		model, err := mdtt.NewUI(append([]mdtt.Option{opt}, opts...)...)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -69,7 +196,7 @@ func createModel(args []string, inplace bool) mdtt.Model {
 
 	} else if len(args) == 0 {
 
-		model, err := mdtt.NewUI()
+		model, err := mdtt.NewUI(opts...)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -79,11 +206,15 @@ func createModel(args []string, inplace bool) mdtt.Model {
 		f, _ := os.Open(args[0])
 		defer f.Close()
 		content, _ := io.ReadAll(f)
-		model, err := mdtt.NewUI(
-			mdtt.WithMarkdown(content),
+		opt, format := tableOption(content, args[0], in)
+		if inplace && format != mdtt.FormatMarkdown {
+			log.Fatal("in-place editing is only supported for markdown files")
+		}
+		model, err := mdtt.NewUI(append([]mdtt.Option{
+			opt,
 			mdtt.WithInplace(inplace),
 			mdtt.WithFilePath(args[0]),
-		)
+		}, opts...)...)
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -110,13 +241,53 @@ func init() {
 		false,
 		"in-place update",
 	)
+	rootCmd.Flags().String(
+		"from",
+		"auto",
+		"input format: auto, markdown, csv or tsv",
+	)
+	rootCmd.Flags().String(
+		"to",
+		mdtt.FormatMarkdown,
+		"output format: "+strings.Join(mdtt.OutputFormats, ", "),
+	)
+	rootCmd.Flags().StringP(
+		"delimiter",
+		"d",
+		"",
+		"field delimiter of CSV input, detected when empty",
+	)
+	rootCmd.Flags().String(
+		"header",
+		"auto",
+		"whether the first CSV record is the header: auto, yes or no",
+	)
+	rootCmd.Flags().Bool(
+		"lazy-quotes",
+		false,
+		"allow quotes in unquoted CSV fields and unescaped quotes in quoted fields",
+	)
+	rootCmd.Flags().String(
+		"config",
+		"",
+		"config file, $XDG_CONFIG_HOME/mdtt/config.toml by default",
+	)
+	rootCmd.Flags().String(
+		"theme",
+		"",
+		"color theme: "+strings.Join(mdtt.ThemeNames(), ", ")+" or a theme of the config file",
+	)
+	rootCmd.PersistentFlags().String(
+		"ambiwidth",
+		"",
+		"width of East Asian ambiguous characters: single or double, detected from the locale when empty",
+	)
 	rootCmd.Flags().BoolP(
 		"help",
 		"h",
 		false,
 		"help for mdtt",
 	)
-	lipgloss.SetColorProfile(termenv.ANSI256)
 }
 
 func createLogger(debug bool) *os.File {
@@ -0,0 +1,210 @@
+package mdtt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// exCommand is a parsed command line such as ":%s/foo/bar/g".
+type exCommand struct {
+	// rng is the range prefix, "%" for the whole table or "'<,'>" for the
+	// last visual selection.
+	rng  string
+	name string
+	bang bool
+	args string
+}
+
+type exCommandFunc func(m *TableModel, c exCommand) (tea.Cmd, error)
+
+// exCommands lists the available commands. A command may be abbreviated
+// down to short characters, the first matching entry wins.
+var exCommands = []struct {
+	name  string
+	short int
+	run   exCommandFunc
+}{
+	{"write", 1, cmdWrite},
+	{"wq", 2, cmdWriteQuit},
+	{"xit", 1, cmdWriteQuit},
+	{"quit", 1, cmdQuit},
+	{"substitute", 1, cmdSubstitute},
+	{"sort", 3, cmdSort},
+	{"fill", 2, cmdFill},
+	{"move", 1, cmdMove},
+	{"set", 2, cmdSet},
+	{"goto", 2, cmdGoto},
+	{"freeze", 3, cmdFreeze},
+	{"tables", 3, cmdTables},
+	{"nohlsearch", 3, cmdNohlsearch},
+	{"help", 1, cmdHelp},
+}
+
+// writeMsg asks to write the table without quitting.
+type writeMsg struct{}
+
+func writeCmd() tea.Cmd {
+	return func() tea.Msg {
+		return writeMsg{}
+	}
+}
+
+func newCommandLine() textinput.Model {
+	ti := textinput.New()
+	ti.Prompt = ":"
+	ti.Cursor.Style = cellCursorStyle
+	return ti
+}
+
+func parseCommand(s string) (exCommand, error) {
+	var c exCommand
+	s = strings.TrimLeft(s, ": ")
+
+	for _, rng := range []string{"%", "'<,'>"} {
+		if strings.HasPrefix(s, rng) {
+			c.rng = rng
+			s = s[len(rng):]
+			break
+		}
+	}
+
+	i := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if i < 0 {
+		i = len(s)
+	}
+	c.name, s = s[:i], s[i:]
+
+	if c.name == "" {
+		// ":12" is a shorthand for ":goto 12"
+		if _, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
+			c.name = "goto"
+			c.args = strings.TrimSpace(s)
+			return c, nil
+		}
+		return c, fmt.Errorf("E492: Not an editor command: %s", s)
+	}
+
+	if strings.HasPrefix(s, "!") {
+		c.bang = true
+		s = s[1:]
+	}
+	c.args = strings.TrimSpace(s)
+	return c, nil
+}
+
+// executeCommand runs the command line s against the table.
+func (m *TableModel) executeCommand(s string) tea.Cmd {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+
+	c, err := parseCommand(s)
+	if err != nil {
+		m.setError(err)
+		return nil
+	}
+
+	for _, ex := range exCommands {
+		if len(c.name) >= ex.short && strings.HasPrefix(ex.name, c.name) {
+			cmd, err := ex.run(m, c)
+			if err != nil {
+				m.setError(err)
+			}
+			return cmd
+		}
+	}
+	m.setError(fmt.Errorf("E492: Not an editor command: %s", c.name))
+	return nil
+}
+
+func cmdWrite(m *TableModel, c exCommand) (tea.Cmd, error) {
+	return writeCmd(), nil
+}
+
+func cmdWriteQuit(m *TableModel, c exCommand) (tea.Cmd, error) {
+	return writeQuitCmd(), nil
+}
+
+func cmdQuit(m *TableModel, c exCommand) (tea.Cmd, error) {
+	if c.bang {
+		return discardCmd(), nil
+	}
+	if m.modified() {
+		return nil, fmt.Errorf("E37: No write since last change (add ! to override)")
+	}
+	return quitCmd(), nil
+}
+
+func cmdTables(m *TableModel, c exCommand) (tea.Cmd, error) {
+	return pickerCmd(), nil
+}
+
+func cmdNohlsearch(m *TableModel, c exCommand) (tea.Cmd, error) {
+	m.search.highlight = false
+	m.updateViewport()
+	return nil, nil
+}
+
+func cmdHelp(m *TableModel, c exCommand) (tea.Cmd, error) {
+	m.enableAllHelp()
+	return nil, nil
+}
+
+func cmdGoto(m *TableModel, c exCommand) (tea.Cmd, error) {
+	fields := strings.Fields(c.args)
+	if len(fields) == 0 || len(fields) > 2 {
+		return nil, fmt.Errorf("usage: goto {row} [column]")
+	}
+
+	y, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid row: %s", fields[0])
+	}
+	if len(fields) == 2 {
+		x, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid column: %s", fields[1])
+		}
+		m.cursor.x = clamp(x-1, 0, len(m.cols)-1)
+	}
+	m.gotoRow(y)
+	return nil, nil
+}
+
+func cmdSet(m *TableModel, c exCommand) (tea.Cmd, error) {
+	for _, arg := range strings.Fields(c.args) {
+		name, value, _ := strings.Cut(arg, "=")
+		switch name {
+		case "align":
+			x0, x1 := m.cursor.x, m.cursor.x
+			if c.rng == "'<,'>" {
+				s := m.lastVisual
+				x0, _, x1, _ = s.rect(s.cursor, len(m.rows), len(m.cols))
+			}
+			if err := m.setAlignment(x0, x1, value); err != nil {
+				return nil, err
+			}
+		case "ignorecase", "ic":
+			m.searchOptions.ignoreCase = true
+		case "noignorecase", "noic":
+			m.searchOptions.ignoreCase = false
+		case "regex":
+			m.searchOptions.regex = true
+		case "noregex":
+			m.searchOptions.regex = false
+		default:
+			return nil, fmt.Errorf("E518: Unknown option: %s", name)
+		}
+	}
+
+	if m.search.pattern != "" {
+		m.search.re, _ = compileSearch(m.search.pattern, m.searchOptions)
+		m.updateViewport()
+	}
+	return nil, nil
+}
@@ -0,0 +1,139 @@
+package mdtt
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCommand(t *testing.T) {
+	testCases := []struct {
+		src  string
+		want exCommand
+	}{
+		{src: "w", want: exCommand{name: "w"}},
+		{src: ":q!", want: exCommand{name: "q", bang: true}},
+		{src: "%s/foo/bar/g", want: exCommand{rng: "%", name: "s", args: "/foo/bar/g"}},
+		{src: "'<,'>fill x", want: exCommand{rng: "'<,'>", name: "fill", args: "x"}},
+		{src: "sort! n", want: exCommand{name: "sort", bang: true, args: "n"}},
+		{src: "goto 12 3", want: exCommand{name: "goto", args: "12 3"}},
+		{src: "12", want: exCommand{name: "goto", args: "12"}},
+		{src: "set align=right", want: exCommand{name: "set", args: "align=right"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.src, func(t *testing.T) {
+			got, err := parseCommand(tc.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(exCommand{})); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestExecuteCommand(t *testing.T) {
+	newTable := func() TableModel {
+		return NewTableModel(
+			WithColumns([]column{
+				{title: NewCell("name"), width: 5},
+				{title: NewCell("note"), width: 5},
+			}),
+			WithNaiveRows([]naiveRow{
+				{"foo", "foo foo"},
+				{"bar", "foo"},
+				{"baz", "qux"},
+			}),
+		)
+	}
+
+	t.Run("sort", func(t *testing.T) {
+		m := newTable()
+		m.executeCommand("sort!")
+		if got := m.rows[0][0].value(); got != "foo" {
+			t.Errorf("got %q, want %q", got, "foo")
+		}
+		m.executeCommand("sort")
+		if got := m.rows[0][0].value(); got != "bar" {
+			t.Errorf("got %q, want %q", got, "bar")
+		}
+	})
+
+	t.Run("substitute", func(t *testing.T) {
+		m := newTable()
+		m.executeCommand("%s/foo/x/")
+		if got := m.rows[0][1].value(); got != "x foo" {
+			t.Errorf("got %q, want %q", got, "x foo")
+		}
+		m.executeCommand("%s/foo/x/g")
+		if got := m.rows[0][1].value(); got != "x x" {
+			t.Errorf("got %q, want %q", got, "x x")
+		}
+		m.executeCommand("s/ba/B/")
+		if got := m.rows[1][0].value(); got != "Br" {
+			t.Errorf("got %q, want %q", got, "Br")
+		}
+		m.executeCommand("s/nothing/x/")
+		if !m.messageErr {
+			t.Error("expected an error for a missing pattern")
+		}
+	})
+
+	t.Run("goto", func(t *testing.T) {
+		m := newTable()
+		m.executeCommand("goto 3 2")
+		if m.cursor != (cursor{x: 1, y: 2}) {
+			t.Errorf("got %+v", m.cursor)
+		}
+		m.executeCommand("0")
+		if m.mode != HEADER {
+			t.Errorf("got mode %d, want HEADER", m.mode)
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		m := newTable()
+		m.executeCommand("set align=right")
+		if got := m.cols[0].alignment; got != "right" {
+			t.Errorf("got %q, want %q", got, "right")
+		}
+		m.executeCommand("set foo")
+		if !m.messageErr {
+			t.Error("expected an error for an unknown option")
+		}
+	})
+
+	t.Run("quit", func(t *testing.T) {
+		m := newTable()
+		if cmd := m.executeCommand("q"); cmd == nil {
+			t.Fatal("expected a quit command")
+		}
+		m.executeCommand("%s/foo/bar/")
+		if cmd := m.executeCommand("q"); cmd != nil {
+			t.Error("quitting a modified table should fail")
+		}
+		if msg := m.executeCommand("q!")(); msg != (discardMsg{}) {
+			t.Errorf("got %T, want discardMsg", msg)
+		}
+	})
+}
+
+func TestCommandLineMode(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{{title: NewCell("a"), width: 3}}),
+		WithNaiveRows([]naiveRow{{"1"}, {"2"}}),
+	)
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(":")})
+	if m.mode != COMMAND {
+		t.Fatalf("got mode %d, want COMMAND", m.mode)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("2")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != NORMAL || m.cursor.y != 1 {
+		t.Errorf("got mode %d cursor %+v", m.mode, m.cursor)
+	}
+}
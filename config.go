@@ -0,0 +1,143 @@
+package mdtt
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is the user configuration, read from a TOML file such as:
+//
+//	theme = "solarized"
+//
+//	[themes.solarized]
+//	accent = "#268BD2"
+//	border = "#586E75"
+//
+//	[keys]
+//	down = ["down", "n"]
+//	up = "e"
+//	write_quit = ["Z Z", "ctrl+s"]
+type Config struct {
+	// ThemeName is the name of the theme, "auto" by default.
+	ThemeName string `toml:"theme"`
+	// Themes are the user themes, by name.
+	Themes map[string]Theme `toml:"themes"`
+	// Keys binds actions to key sequences, in place of their default
+	// ones.
+	Keys map[string]KeySequences `toml:"keys"`
+}
+
+// KeySequences are the key sequences bound to an action, their keys
+// separated by spaces. A single sequence may be given as a string.
+type KeySequences []string
+
+// UnmarshalTOML reads a string or an array of strings.
+func (s *KeySequences) UnmarshalTOML(v any) error {
+	switch v := v.(type) {
+	case string:
+		*s = KeySequences{v}
+	case []any:
+		*s = make(KeySequences, len(v))
+		for i, e := range v {
+			str, ok := e.(string)
+			if !ok {
+				return fmt.Errorf("key sequence is not a string: %v", e)
+			}
+			(*s)[i] = str
+		}
+	default:
+		return fmt.Errorf("key sequences are not a string or an array: %v", v)
+	}
+	return nil
+}
+
+// DefaultConfigPath returns the path of the config file,
+// $XDG_CONFIG_HOME/mdtt/config.toml or ~/.config/mdtt/config.toml.
+func DefaultConfigPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "mdtt", "config.toml")
+}
+
+// LoadConfig reads the config file at path. Unknown settings and invalid or
+// conflicting key bindings are errors.
+func LoadConfig(path string) (Config, error) {
+	var c Config
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	md, err := toml.Decode(string(b), &c)
+	if err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return c, fmt.Errorf("%s: unknown setting %q", path, undecoded[0].String())
+	}
+	if _, err := c.keyMap(); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	if err := c.validateThemes(); err != nil {
+		return c, fmt.Errorf("%s: %w", path, err)
+	}
+	return c, nil
+}
+
+// keyMap returns the default key map with the key bindings of the config.
+func (c Config) keyMap() (keyMap, error) {
+	km := defaultKeyMap()
+	names := make([]string, 0, len(c.Keys))
+	for name := range c.Keys {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if err := km.bind(name, c.Keys[name]); err != nil {
+			return km, err
+		}
+	}
+	if err := km.validate(); err != nil {
+		return km, fmt.Errorf("conflicting key bindings: %w", err)
+	}
+	return km, nil
+}
+
+// validateThemes reports an unknown theme name and invalid user themes.
+func (c Config) validateThemes() error {
+	names := []string{c.ThemeName}
+	for name := range c.Themes {
+		names = append(names, name)
+	}
+	slices.Sort(names[1:])
+	for _, name := range names {
+		if name == "" || name == ThemeAuto {
+			continue
+		}
+		if _, err := c.resolveTheme(name, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithConfig applies the user configuration to every table.
+func WithConfig(c Config) Option {
+	return func(m *Model) error {
+		km, err := c.keyMap()
+		if err != nil {
+			return err
+		}
+		m.keys = km
+		return nil
+	}
+}
@@ -0,0 +1,227 @@
+package mdtt
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "remapped keys",
+			config: "[keys]\ndown = [\"down\", \"n\"]\nup = \"e\"\nnext_match = \"ctrl+n\"\ntop = \"g g\"\n",
+		},
+		{
+			name:   "unbound action",
+			config: "[keys]\nsearch = []\n",
+		},
+		{
+			name:   "unknown action",
+			config: "[keys]\njump = \"J\"\n",
+			err:    `config.toml: unknown action "jump"`,
+		},
+		{
+			name:   "unknown setting",
+			config: "[key]\nup = \"e\"\n",
+			err:    `config.toml: unknown setting "key"`,
+		},
+		{
+			name:   "invalid key",
+			config: "[keys]\nup = \"ctl+e\"\n",
+			err:    `config.toml: invalid key "ctl+e" for up`,
+		},
+		{
+			name:   "empty sequence",
+			config: "[keys]\nup = \" \"\n",
+			err:    `config.toml: empty key sequence for up`,
+		},
+		{
+			name:   "shared key",
+			config: "[keys]\ndown = \"n\"\n",
+			err:    `config.toml: conflicting key bindings: "n" is bound to both down and next_match`,
+		},
+		{
+			name:   "prefix of another sequence",
+			config: "[keys]\ntop = \"g g\"\nbottom = \"g\"\n",
+			err:    `config.toml: conflicting key bindings: "g" of bottom begins "g g" of top`,
+		},
+		{
+			name:   "count",
+			config: "[keys]\nup = \"g 1\"\n",
+			err:    `config.toml: conflicting key bindings: up: key "1" is taken by counts`,
+		},
+		{
+			name:   "sequence leaving insert mode",
+			config: "[keys]\nnormal_mode = [\"esc\", \"j k\"]\n",
+			err:    `config.toml: conflicting key bindings: normal_mode: "j k" is a key sequence, it must be a single key`,
+		},
+		{
+			name:   "theme",
+			config: "theme = \"paper\"\n[themes.paper]\nbase = \"light\"\naccent = \"#000000\"\n",
+		},
+		{
+			name:   "unknown theme",
+			config: "theme = \"sepia\"\n",
+			err:    `config.toml: unknown theme "sepia"`,
+		},
+		{
+			name:   "invalid theme color",
+			config: "[themes.paper]\nborder = \"grey\"\n",
+			err:    `config.toml: theme "paper": invalid color "grey" for border`,
+		},
+		{
+			name:   "not a string",
+			config: "[keys]\nup = 3\n",
+			err:    "config.toml: toml: line 2 (last key \"keys.up\"): key sequences are not a string or an array: 3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "config.toml")
+			if err := os.WriteFile(path, []byte(tc.config), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := LoadConfig(path)
+			var got string
+			if err != nil {
+				got = strings.TrimPrefix(err.Error(), dir+string(filepath.Separator))
+			}
+			if got != tc.err {
+				t.Errorf("got error %q, want %q", got, tc.err)
+			}
+		})
+	}
+}
+
+func TestDefaultKeyMap(t *testing.T) {
+	if err := defaultKeyMap().validate(); err != nil {
+		t.Errorf("default key map: %v", err)
+	}
+}
+
+func TestHelpKey(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{{title: NewCell("a"), width: 5}}),
+		WithNaiveRows([]naiveRow{{"1"}}),
+	)
+	m, _ = m.Update(keys("?")[0])
+	if !m.help.ShowAll {
+		t.Errorf("? does not show the help")
+	}
+	m, _ = m.Update(keys("?")[0])
+	if m.help.ShowAll {
+		t.Errorf("? does not hide the help")
+	}
+}
+
+func TestConfigKeys(t *testing.T) {
+	config := Config{Keys: map[string]KeySequences{
+		"down":        {"down", "n"},
+		"next_match":  {"ctrl+n"},
+		"top":         {"g g"},
+		"delete_rows": {"space d"},
+		"page_down":   {"f", "pgdown"},
+		"write_quit":  {"Z Z", "ctrl+s"},
+	}}
+	m, err := NewUI(
+		WithMarkdown([]byte("| a |\n| - |\n| 1 |\n| 2 |\n| 3 |\n| 4 |\n")),
+		WithConfig(config),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	table := m.table
+
+	for _, k := range keys("2n") {
+		table, _ = table.Update(k)
+	}
+	if table.cursor.y != 2 {
+		t.Errorf("got cursor row %d, want 2", table.cursor.y)
+	}
+
+	// a sequence waits for its next key
+	for _, k := range keys("g") {
+		table, _ = table.Update(k)
+	}
+	if table.cursor.y != 2 || table.pending.seq != "g" {
+		t.Errorf("got cursor row %d and pending %+v", table.cursor.y, table.pending)
+	}
+	for _, k := range keys("g") {
+		table, _ = table.Update(k)
+	}
+	if table.cursor.y != 0 {
+		t.Errorf("got cursor row %d, want 0", table.cursor.y)
+	}
+
+	for _, k := range keys("2 d") {
+		table, _ = table.Update(k)
+	}
+	if diff := cmp.Diff([][]string{{"3"}, {"4"}}, values(table.rows)); diff != "" {
+		t.Errorf("rows differ: (-want +got)\n%s", diff)
+	}
+
+	// the default sequence is replaced
+	for _, k := range keys("dd") {
+		table, _ = table.Update(k)
+	}
+	if len(table.rows) != 2 {
+		t.Errorf("got %d rows, want 2", len(table.rows))
+	}
+
+	_, cmd := table.Update(tea.KeyMsg{Type: tea.KeyCtrlS})
+	if _, ok := cmd().(writeQuitMsg); !ok {
+		t.Errorf("ctrl+s does not write and quit")
+	}
+
+	// the help shows the bound keys
+	if got := table.keys.lineDown.Help().Key; got != "↓/n" {
+		t.Errorf("got help %q, want %q", got, "↓/n")
+	}
+	if got := table.keys.deleteRows.Help().Key; got != "space d" {
+		t.Errorf("got help %q, want %q", got, "space d")
+	}
+}
+
+func TestHelpKeys(t *testing.T) {
+	testCases := []struct {
+		keys []string
+		want string
+	}{
+		{keys: []string{"up", "k"}, want: "↑/k"},
+		{keys: []string{"d d"}, want: "dd"},
+		{keys: []string{"Z Z", "ctrl+s"}, want: "ZZ/ctrl+s"},
+		{keys: []string{"space d"}, want: "space d"},
+		{keys: []string{"f", "pgdown", "space"}, want: "f/pgdown/space"},
+	}
+
+	for _, tc := range testCases {
+		if got := helpKeys(tc.keys); got != tc.want {
+			t.Errorf("helpKeys(%q) = %q, want %q", tc.keys, got, tc.want)
+		}
+	}
+}
+
+func TestDefaultConfigPath(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "/tmp/config")
+	if got := DefaultConfigPath(); got != "/tmp/config/mdtt/config.toml" {
+		t.Errorf("got %q", got)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "/home/user")
+	if got := DefaultConfigPath(); got != "/home/user/.config/mdtt/config.toml" {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,281 @@
+package mdtt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Enum of header row handling for CSV input
+const (
+	CSVHeaderAuto = iota
+	CSVHeaderYes
+	CSVHeaderNo
+)
+
+// Enum of input formats
+const (
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+	FormatTSV      = "tsv"
+)
+
+// csvDelimiters are the delimiters tried when detecting the delimiter of CSV
+// input, in order of preference.
+var csvDelimiters = []rune{',', '\t', ';'}
+
+// sniffRecords is the number of records read to detect the delimiter.
+const sniffRecords = 20
+
+// CSVOptions configures how delimiter-separated values are read.
+type CSVOptions struct {
+	// Delimiter separates the fields. It is detected from the input when
+	// zero.
+	Delimiter rune
+	// Header is one of CSVHeaderAuto, CSVHeaderYes and CSVHeaderNo.
+	Header int
+	// LazyQuotes allows quotes in unquoted fields and unescaped quotes in
+	// quoted fields.
+	LazyQuotes bool
+}
+
+// WithCSV sets the table from delimiter-separated values.
+func WithCSV(b []byte, opts CSVOptions) Option {
+	return func(m *Model) error {
+		t, err := parseCSV(b, opts)
+		if err != nil {
+			return err
+		}
+		m.table = t
+		m.tables = []TableModel{t}
+		m.choose = 0
+		m.preview = false
+		return nil
+	}
+}
+
+// DetectFormat guesses the format of the input from the extension of path,
+// then from its content. Input with markdown tables, or without a consistent
+// delimiter, is markdown.
+func DetectFormat(path string, b []byte) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".tsv", ".tab":
+		return FormatTSV
+	case ".md", ".markdown":
+		return FormatMarkdown
+	}
+
+	if len(parse(b)) > 0 {
+		return FormatMarkdown
+	}
+	switch sniffDelimiter(b) {
+	case ',', ';':
+		return FormatCSV
+	case '\t':
+		return FormatTSV
+	}
+	return FormatMarkdown
+}
+
+// ParseDelimiter parses the value of a delimiter option, which is a single
+// character or one of "\t", "tab", "comma" and "semicolon".
+func ParseDelimiter(s string) (rune, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return 0, nil
+	case `\t`, "tab":
+		return '\t', nil
+	case "comma":
+		return ',', nil
+	case "semicolon":
+		return ';', nil
+	}
+
+	r := []rune(s)
+	if len(r) != 1 || r[0] == '"' || r[0] == '\n' || r[0] == '\r' {
+		return 0, fmt.Errorf("invalid delimiter: %q", s)
+	}
+	return r[0], nil
+}
+
+// parseCSV builds a table from delimiter-separated values. Short records are
+// padded with empty cells.
+func parseCSV(b []byte, opts CSVOptions) (TableModel, error) {
+	delim := opts.Delimiter
+	if delim == 0 {
+		delim = sniffDelimiter(b)
+	}
+	if delim == 0 {
+		delim = ','
+	}
+
+	records, err := readCSV(b, delim, opts.LazyQuotes, -1)
+	if err != nil {
+		return TableModel{}, err
+	}
+	if len(records) == 0 {
+		return TableModel{}, fmt.Errorf("no records found")
+	}
+
+	var ncols int
+	for _, r := range records {
+		ncols = max(ncols, len(r))
+	}
+	for i, r := range records {
+		for j := range r {
+			r[j] = escapeCell(r[j])
+		}
+		for len(r) < ncols {
+			r = append(r, "")
+		}
+		records[i] = r
+	}
+
+	header := opts.Header == CSVHeaderYes ||
+		(opts.Header == CSVHeaderAuto && hasHeader(records))
+	var titles []string
+	if header {
+		titles, records = records[0], records[1:]
+	} else {
+		for i := range ncols {
+			titles = append(titles, columnName(i))
+		}
+	}
+
+	var rows []naiveRow
+	for _, r := range records {
+		rows = append(rows, r)
+	}
+
+	var cols []column
+	for i, title := range titles {
+		width := cellWidth(title)
+		for _, r := range rows {
+			width = max(cellWidth(r[i]), width)
+		}
+		cols = append(cols, column{
+			title:     NewCell(title),
+			width:     width + widthPadding,
+			alignment: "none",
+		})
+	}
+
+	t := NewTableModel(
+		WithColumns(cols),
+		WithNaiveRows(rows),
+		WithFocused(true),
+		WithHeight(len(rows)+1),
+		WithStyles(defaultStyles()),
+	)
+	if len(rows) == 0 {
+		t.switchMode(HEADER)
+	}
+	return t, nil
+}
+
+// readCSV reads at most n records, or every record when n is negative.
+func readCSV(b []byte, delim rune, lazyQuotes bool, n int) ([][]string, error) {
+	r := csv.NewReader(bytes.NewReader(b))
+	r.Comma = delim
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = lazyQuotes
+
+	var records [][]string
+	for n < 0 || len(records) < n {
+		rec, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		records = append(records, rec)
+	}
+	return records, nil
+}
+
+// sniffDelimiter returns the delimiter that splits the first records into the
+// same number of fields, preferring the one with more fields. It returns 0
+// when no delimiter splits them.
+func sniffDelimiter(b []byte) rune {
+	var best rune
+	var bestFields int
+	for _, d := range csvDelimiters {
+		records, err := readCSV(b, d, true, sniffRecords)
+		if err != nil || len(records) == 0 {
+			continue
+		}
+		fields := len(records[0])
+		for _, r := range records {
+			if len(r) != fields {
+				fields = 0
+				break
+			}
+		}
+		if fields > 1 && fields > bestFields {
+			best, bestFields = d, fields
+		}
+	}
+	return best
+}
+
+// hasHeader guesses whether the first record is a header. A header has
+// unique, non-empty values, and is not a number in a column of numbers.
+func hasHeader(records [][]string) bool {
+	seen := make(map[string]bool)
+	for _, v := range records[0] {
+		v = strings.TrimSpace(v)
+		if v == "" || seen[v] {
+			return false
+		}
+		seen[v] = true
+	}
+	if len(records) == 1 {
+		return true
+	}
+
+	for i, v := range records[0] {
+		numeric := true
+		for _, r := range records[1:] {
+			if r[i] != "" && !isNumber(r[i]) {
+				numeric = false
+				break
+			}
+		}
+		if numeric && isNumber(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", ""), 64)
+	return err == nil
+}
+
+// escapeCell converts a CSV field to the text of a markdown cell. Pipes are
+// escaped and line breaks become <br>.
+func escapeCell(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = strings.ReplaceAll(s, "\n", "<br>")
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// columnName returns the spreadsheet name of the i-th column: A, B, ..., Z,
+// AA, AB and so on.
+func columnName(i int) string {
+	var name []byte
+	for i++; i > 0; i = (i - 1) / 26 {
+		name = append([]byte{byte('A' + (i-1)%26)}, name...)
+	}
+	return string(name)
+}
@@ -0,0 +1,157 @@
+package mdtt
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCSV(t *testing.T) {
+	testCases := []struct {
+		name   string
+		src    string
+		opts   CSVOptions
+		titles []string
+		rows   [][]string
+	}{
+		{
+			name:   "header detected",
+			src:    "name,age\nfoo,12\nbar,3\n",
+			titles: []string{"name", "age"},
+			rows:   [][]string{{"foo", "12"}, {"bar", "3"}},
+		},
+		{
+			name:   "numbers are not a header",
+			src:    "1,2\n3,4\n",
+			titles: []string{"A", "B"},
+			rows:   [][]string{{"1", "2"}, {"3", "4"}},
+		},
+		{
+			name:   "header forced",
+			src:    "1,2\n3,4\n",
+			opts:   CSVOptions{Header: CSVHeaderYes},
+			titles: []string{"1", "2"},
+			rows:   [][]string{{"3", "4"}},
+		},
+		{
+			name:   "no header",
+			src:    "name,age\nfoo,12\n",
+			opts:   CSVOptions{Header: CSVHeaderNo},
+			titles: []string{"A", "B"},
+			rows:   [][]string{{"name", "age"}, {"foo", "12"}},
+		},
+		{
+			name:   "tab detected",
+			src:    "a\tb\tc, d\n1\t2\t3, 4\n",
+			titles: []string{"a", "b", "c, d"},
+			rows:   [][]string{{"1", "2", "3, 4"}},
+		},
+		{
+			name:   "semicolon",
+			src:    "a;b\n1;2\n",
+			opts:   CSVOptions{Delimiter: ';'},
+			titles: []string{"a", "b"},
+			rows:   [][]string{{"1", "2"}},
+		},
+		{
+			name:   "quotes, pipes and line breaks",
+			src:    "a,b\n\"x, \"\"y\"\"\",\"1|2\"\n\"line\nbreak\",z\n",
+			titles: []string{"a", "b"},
+			rows:   [][]string{{`x, "y"`, `1\|2`}, {"line<br>break", "z"}},
+		},
+		{
+			name:   "ragged records",
+			src:    "a,b,c\n1\n1,2\n",
+			titles: []string{"a", "b", "c"},
+			rows:   [][]string{{"1", "", ""}, {"1", "2", ""}},
+		},
+		{
+			name:   "lazy quotes",
+			src:    "a,b\n1 \"x\",2\n",
+			opts:   CSVOptions{Delimiter: ',', LazyQuotes: true},
+			titles: []string{"a", "b"},
+			rows:   [][]string{{`1 "x"`, "2"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := parseCSV([]byte(tc.src), tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var titles []string
+			for _, c := range m.cols {
+				titles = append(titles, c.title.value())
+			}
+			if diff := cmp.Diff(tc.titles, titles); diff != "" {
+				t.Errorf("titles differ: (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.rows, values(m.rows)); diff != "" {
+				t.Errorf("rows differ: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseCSVError(t *testing.T) {
+	if _, err := parseCSV([]byte("a,b\n1 \"x\",2\n"), CSVOptions{Delimiter: ','}); err == nil {
+		t.Error("expected an error for a bare quote")
+	}
+	if _, err := parseCSV(nil, CSVOptions{}); err == nil {
+		t.Error("expected an error for empty input")
+	}
+}
+
+func TestDetectFormat(t *testing.T) {
+	testCases := []struct {
+		path string
+		src  string
+		want string
+	}{
+		{path: "a.csv", src: "| a |\n| - |\n", want: FormatCSV},
+		{path: "a.TSV", want: FormatTSV},
+		{path: "a.md", src: "a,b\n1,2\n", want: FormatMarkdown},
+		{src: "| a | b |\n| - | - |\n| 1 | 2 |\n", want: FormatMarkdown},
+		{src: "a,b\n1,2\n", want: FormatCSV},
+		{src: "a\tb\n1\t2\n", want: FormatTSV},
+		{src: "# title\n\nsome text\n", want: FormatMarkdown},
+	}
+
+	for _, tc := range testCases {
+		if got := DetectFormat(tc.path, []byte(tc.src)); got != tc.want {
+			t.Errorf("DetectFormat(%q, %q) = %q, want %q", tc.path, tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	testCases := []struct {
+		src     string
+		want    rune
+		wantErr bool
+	}{
+		{src: "", want: 0},
+		{src: `\t`, want: '\t'},
+		{src: "tab", want: '\t'},
+		{src: ";", want: ';'},
+		{src: "|", want: '|'},
+		{src: "ab", wantErr: true},
+		{src: `"`, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := ParseDelimiter(tc.src)
+		if (err != nil) != tc.wantErr || got != tc.want {
+			t.Errorf("ParseDelimiter(%q) = %q, %v", tc.src, got, err)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %q, want %q", i, got, want)
+		}
+	}
+}
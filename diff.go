@@ -0,0 +1,155 @@
+package mdtt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around a change.
+const diffContext = 3
+
+// lineEdit is a line of a diff: kept (' '), deleted ('-') or inserted ('+').
+type lineEdit struct {
+	op   byte
+	line string
+}
+
+// UnifiedDiff returns the changes from a to b in the unified diff format,
+// or an empty string when they are equal. name is shown in the file headers.
+func UnifiedDiff(name string, a, b []byte) string {
+	if string(a) == string(b) {
+		return ""
+	}
+	edits := diffLines(splitLines(string(a)), splitLines(string(b)))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", name, name)
+
+	// position of each edit in a and b, counted from 1
+	ai, bi := make([]int, len(edits)+1), make([]int, len(edits)+1)
+	ai[0], bi[0] = 1, 1
+	for i, e := range edits {
+		ai[i+1], bi[i+1] = ai[i], bi[i]
+		if e.op != '+' {
+			ai[i+1]++
+		}
+		if e.op != '-' {
+			bi[i+1]++
+		}
+	}
+
+	for i := 0; i < len(edits); {
+		if edits[i].op == ' ' {
+			i++
+			continue
+		}
+
+		// a hunk runs until diffContext lines after the last change that is
+		// followed by more than twice diffContext unchanged lines.
+		start := max(i-diffContext, 0)
+		end := i
+		for j := i; j < len(edits) && j <= end+2*diffContext+1; j++ {
+			if edits[j].op != ' ' {
+				end = j
+			}
+		}
+		end = min(end+diffContext+1, len(edits))
+
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n",
+			hunkRange(ai[start], ai[end]-ai[start]),
+			hunkRange(bi[start], bi[end]-bi[start]))
+		for _, e := range edits[start:end] {
+			sb.WriteByte(e.op)
+			sb.WriteString(e.line)
+			if !strings.HasSuffix(e.line, "\n") {
+				sb.WriteString("\n\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return sb.String()
+}
+
+func hunkRange(start, n int) string {
+	if n == 0 {
+		start--
+	}
+	if n == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, n)
+}
+
+// splitLines splits s after each line break.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// diffLines returns the shortest edit script from a to b, found with the
+// Myers algorithm.
+func diffLines(a, b []string) []lineEdit {
+	n, m := len(a), len(b)
+	off := n + m + 1
+	v := make([]int, 2*off+1)
+
+	// trace[d] holds v[-d:d+1] after the d-th step
+	var trace [][]int
+	for d, done := 0, false; !done; d++ {
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			done = done || (k == n-m && x >= n)
+		}
+		trace = append(trace, append([]int(nil), v[off-d:off+d+1]...))
+	}
+
+	var edits []lineEdit
+	x, y := n, m
+	for d := len(trace) - 1; d > 0; d-- {
+		prev := trace[d-1]
+		k := x - y
+		var pk int
+		if k == -d || (k != d && prev[k-1+d-1] < prev[k+1+d-1]) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := prev[pk+d-1]
+		py := px - pk
+		for x > px && y > py {
+			x--
+			y--
+			edits = append(edits, lineEdit{' ', a[x]})
+		}
+		if pk == k+1 {
+			y--
+			edits = append(edits, lineEdit{'+', b[y]})
+		} else {
+			x--
+			edits = append(edits, lineEdit{'-', a[x]})
+		}
+	}
+	for x > 0 {
+		x--
+		edits = append(edits, lineEdit{' ', a[x]})
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
@@ -0,0 +1,245 @@
+package mdtt
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	gmhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// Enum of output formats besides the input formats
+const (
+	FormatJSON = "json"
+	FormatHTML = "html"
+)
+
+// OutputFormats lists the formats a table can be printed in.
+var OutputFormats = []string{FormatMarkdown, FormatCSV, FormatTSV, FormatJSON, FormatHTML}
+
+// WithOutputFormat sets the format the tables are printed in.
+func WithOutputFormat(f string) Option {
+	return func(m *Model) error {
+		for _, o := range OutputFormats {
+			if f == o {
+				m.format = f
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown output format: %s", f)
+	}
+}
+
+// renderFormat renders the table in format, which is one of OutputFormats.
+// An unedited table is rendered as markdown by its source.
+func renderFormat(t TableModel, format string) (string, error) {
+	switch format {
+	case FormatCSV:
+		return renderCSV(t, ',')
+	case FormatTSV:
+		return renderCSV(t, '\t')
+	case FormatJSON:
+		return renderJSON(t)
+	case FormatHTML:
+		return renderHTML(t)
+	}
+	if t.unedited() {
+		return t.source, nil
+	}
+	tw := tableWriter{}
+	tw.render(t)
+	return tw.text, nil
+}
+
+// renderCSV renders the table as RFC 4180 CSV, or as TSV for a tab delimiter.
+func renderCSV(t TableModel, delim rune) (string, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	w.Comma = delim
+	w.UseCRLF = delim == ','
+
+	var header []string
+	for _, c := range t.cols {
+		header = append(header, plainText(c.title.value()))
+	}
+	if err := w.Write(header); err != nil {
+		return "", err
+	}
+	for _, r := range t.rows {
+		var record []string
+		for _, c := range r {
+			record = append(record, plainText(c.value()))
+		}
+		if err := w.Write(record); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return buf.String(), w.Error()
+}
+
+// renderJSON renders the table as an array of objects keyed by the header
+// titles, keeping the order of the columns. Empty titles are replaced by the
+// spreadsheet name of the column and duplicate ones get a numeric suffix.
+func renderJSON(t TableModel) (string, error) {
+	var buf bytes.Buffer
+	writeJSON(&buf, t)
+	return indentJSON(buf.Bytes())
+}
+
+// renderJSONTables renders several tables as one array of the arrays of
+// renderJSON, so that the output stays a single JSON value.
+func renderJSONTables(tables []TableModel) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, t := range tables {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		writeJSON(&buf, t)
+	}
+	buf.WriteString("]")
+	return indentJSON(buf.Bytes())
+}
+
+// writeJSON writes the rows of the table to buf as compact JSON.
+func writeJSON(buf *bytes.Buffer, t TableModel) {
+	keys := jsonKeys(t.cols)
+
+	buf.WriteString("[")
+	for y, r := range t.rows {
+		if y > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("{")
+		for x, c := range r {
+			if x > 0 {
+				buf.WriteString(",")
+			}
+			k, _ := json.Marshal(keys[x])
+			v, _ := json.Marshal(plainText(c.value()))
+			buf.Write(k)
+			buf.WriteString(":")
+			buf.Write(v)
+		}
+		buf.WriteString("}")
+	}
+	buf.WriteString("]")
+}
+
+func indentJSON(b []byte) (string, error) {
+	var out bytes.Buffer
+	if err := json.Indent(&out, b, "", "  "); err != nil {
+		return "", err
+	}
+	out.WriteString("\n")
+	return out.String(), nil
+}
+
+func jsonKeys(cols []column) []string {
+	seen := make(map[string]int)
+	var keys []string
+	for i, c := range cols {
+		k := plainText(c.title.value())
+		if k == "" {
+			k = columnName(i)
+		}
+		seen[k]++
+		if n := seen[k]; n > 1 {
+			k = fmt.Sprintf("%s_%d", k, n)
+		}
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// renderHTML renders the table as an HTML table. The cells are rendered as
+// inline markdown and aligned by the alignment of their column.
+func renderHTML(t TableModel) (string, error) {
+	var sb strings.Builder
+	sb.WriteString("<table>\n  <thead>\n    <tr>\n")
+	for _, c := range t.cols {
+		text, err := inlineHTML(c.title.value())
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&sb, "      <th%s>%s</th>\n", alignAttr(c.alignment), text)
+	}
+	sb.WriteString("    </tr>\n  </thead>\n")
+
+	if len(t.rows) > 0 {
+		sb.WriteString("  <tbody>\n")
+		for _, r := range t.rows {
+			sb.WriteString("    <tr>\n")
+			for x, c := range r {
+				text, err := inlineHTML(c.value())
+				if err != nil {
+					return "", err
+				}
+				fmt.Fprintf(&sb, "      <td%s>%s</td>\n", alignAttr(t.cols[x].alignment), text)
+			}
+			sb.WriteString("    </tr>\n")
+		}
+		sb.WriteString("  </tbody>\n")
+	}
+	sb.WriteString("</table>\n")
+	return sb.String(), nil
+}
+
+func alignAttr(a string) string {
+	switch a {
+	case "left", "center", "right":
+		return fmt.Sprintf(` style="text-align: %s"`, a)
+	}
+	return ""
+}
+
+// inlineHTML renders the markdown of a cell to HTML. Raw HTML in the cell,
+// such as <br>, is kept.
+func inlineHTML(s string) (string, error) {
+	if strings.TrimSpace(s) == "" {
+		return "", nil
+	}
+	md := goldmark.New(
+		goldmark.WithExtensions(extension.GFM),
+		goldmark.WithRendererOptions(gmhtml.WithUnsafe()),
+	)
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(blockSafe(s)), &buf); err != nil {
+		return "", err
+	}
+	out := strings.TrimSpace(buf.String())
+	out = strings.TrimPrefix(out, "<p>")
+	out = strings.TrimSuffix(out, "</p>")
+	return out, nil
+}
+
+// blockMarker matches the start of a line that would make a markdown block
+// other than a paragraph: headings, block quotes, list items and code fences.
+var blockMarker = regexp.MustCompile("^(#{1,6}(\\s|$)|>|[-+*](\\s|$)|[0-9]{1,9}[.)](\\s|$)|```|~~~)")
+
+// blockSafe escapes the start of s when it would make a block other than a
+// paragraph, so that a cell is always rendered as inline content.
+func blockSafe(s string) string {
+	s = strings.TrimSpace(s)
+	loc := blockMarker.FindStringIndex(s)
+	if loc == nil {
+		return s
+	}
+	// escape the punctuation of the marker, which is after the digits of an
+	// ordered list item
+	i := strings.IndexFunc(s, func(r rune) bool { return !isDigit(r) })
+	return s[:i] + `\` + s[i:]
+}
+
+// plainText converts the markdown of a cell to plain text for CSV and JSON:
+// escaped pipes are unescaped and <br> becomes a line break.
+func plainText(s string) string {
+	s = strings.ReplaceAll(s, `\|`, "|")
+	return lineBreak.ReplaceAllString(s, "\n")
+}
@@ -0,0 +1,132 @@
+package mdtt
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderFormat(t *testing.T) {
+	table := NewTableModel(
+		WithColumns([]column{
+			{title: NewCell("name"), width: 10, alignment: "left"},
+			{title: NewCell("note"), width: 10, alignment: "none"},
+			{title: NewCell("qty"), width: 5, alignment: "right"},
+		}),
+		WithNaiveRows([]naiveRow{
+			{"foo", `a\|b, "c"`, "1"},
+			{"**bar**", "x<br>y", "22"},
+		}),
+	)
+
+	testCases := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatCSV,
+			want: "name,note,qty\r\n" +
+				"foo,\"a|b, \"\"c\"\"\",1\r\n" +
+				"**bar**,\"x\r\ny\",22\r\n",
+		},
+		{
+			format: FormatTSV,
+			want: "name\tnote\tqty\n" +
+				"foo\t\"a|b, \"\"c\"\"\"\t1\n" +
+				"**bar**\t\"x\ny\"\t22\n",
+		},
+		{
+			format: FormatJSON,
+			want: `[
+  {
+    "name": "foo",
+    "note": "a|b, \"c\"",
+    "qty": "1"
+  },
+  {
+    "name": "**bar**",
+    "note": "x\ny",
+    "qty": "22"
+  }
+]
+`,
+		},
+		{
+			format: FormatHTML,
+			want: `<table>
+  <thead>
+    <tr>
+      <th style="text-align: left">name</th>
+      <th>note</th>
+      <th style="text-align: right">qty</th>
+    </tr>
+  </thead>
+  <tbody>
+    <tr>
+      <td style="text-align: left">foo</td>
+      <td>a|b, &quot;c&quot;</td>
+      <td style="text-align: right">1</td>
+    </tr>
+    <tr>
+      <td style="text-align: left"><strong>bar</strong></td>
+      <td>x<br>y</td>
+      <td style="text-align: right">22</td>
+    </tr>
+  </tbody>
+</table>
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			got, err := renderFormat(table, tc.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestJSONKeys(t *testing.T) {
+	cols := []column{
+		{title: NewCell("a")},
+		{title: NewCell("")},
+		{title: NewCell("a")},
+		{title: NewCell(`x\|y`)},
+	}
+	want := []string{"a", "B", "a_2", "x|y"}
+	if diff := cmp.Diff(want, jsonKeys(cols)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestInlineHTML(t *testing.T) {
+	testCases := []struct {
+		src  string
+		want string
+	}{
+		{src: "", want: ""},
+		{src: "`code`", want: "<code>code</code>"},
+		{src: "# not a heading", want: "# not a heading"},
+		{src: "- not a list", want: "- not a list"},
+		{src: "1. not a list", want: "1. not a list"},
+		{src: "-5", want: "-5"},
+		{src: "> quote", want: "&gt; quote"},
+		{src: "<b>raw</b>", want: "<b>raw</b>"},
+		{src: "a & b", want: "a &amp; b"},
+	}
+
+	for _, tc := range testCases {
+		got, err := inlineHTML(tc.src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("inlineHTML(%q) = %q, want %q", tc.src, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package mdtt
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// Format re-renders every table of the markdown in src with consistent
+// column padding and returns the result. The text around the tables is kept
+// as is, and so are the tables that cannot be located, such as the ones in
+// block quotes. It fails rather than change the content of a table.
+func Format(src []byte) ([]byte, error) {
+	tables := parse(src)
+	if len(tables) == 0 {
+		return src, nil
+	}
+
+	ranges := locateTables(src)
+	texts := make(map[int]string)
+	for i, t := range tables {
+		if _, ok := ranges[i]; !ok {
+			continue
+		}
+		tw := tableWriter{}
+		tw.render(t)
+		texts[i] = tw.text
+	}
+	out, err := replaceTables(bytes.NewReader(src), texts)
+	if err != nil {
+		return nil, err
+	}
+
+	formatted := parse(out)
+	if len(formatted) != len(tables) {
+		return nil, fmt.Errorf("formatting changes the number of tables")
+	}
+	for i := range tables {
+		if !sameContent(tables[i], formatted[i]) {
+			return nil, fmt.Errorf("formatting changes the content of table %d", i+1)
+		}
+	}
+	return out, nil
+}
+
+// sameContent reports whether a and b have the same titles, alignments and
+// cells.
+func sameContent(a, b TableModel) bool {
+	if len(a.cols) != len(b.cols) || len(a.rows) != len(b.rows) {
+		return false
+	}
+	for i := range a.cols {
+		if a.cols[i].title.value() != b.cols[i].title.value() ||
+			a.cols[i].alignment != b.cols[i].alignment {
+			return false
+		}
+	}
+	for y := range a.rows {
+		for x := range a.rows[y] {
+			if a.rows[y][x].value() != b.rows[y][x].value() {
+				return false
+			}
+		}
+	}
+	return true
+}
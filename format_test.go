@@ -0,0 +1,96 @@
+package mdtt
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "pad columns",
+			src:  "# Title\n\n|a|b|\n|-|:-:|\n|foo|x|\n\ntext\n",
+			want: "# Title\n\n| a   | b |\n| --- |:-:|\n| foo | x |\n\ntext\n",
+		},
+		{
+			name: "every table",
+			src:  "|a|\n|-|\n|bb|\n\n|c|\n|--:|\n|d|\n",
+			want: "| a  |\n| -- |\n| bb |\n\n| c |\n| -:|\n| d |\n",
+		},
+		{
+			name: "formatted",
+			src:  "| a   | b |\n| --- | - |\n| foo | x |\n",
+			want: "| a   | b |\n| --- | - |\n| foo | x |\n",
+		},
+		{
+			name: "no table",
+			src:  "# Title\n\ntext",
+			want: "# Title\n\ntext",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Format([]byte(tc.src))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatIdempotent(t *testing.T) {
+	for _, path := range []string{"testdata/01.md", "testdata/02.md", "testdata/replace04.md"} {
+		src, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		once, err := Format(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		twice, err := Format(once)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(once), string(twice)); diff != "" {
+			t.Errorf("%s differs: (-once +twice)\n%s", path, diff)
+		}
+	}
+}
+
+func TestFormatBlockQuote(t *testing.T) {
+	// the tables in block quotes are not located, so they are kept as is
+	testCases := []struct {
+		src  string
+		want string
+	}{
+		{
+			src:  "|a|\n|-|\n|b|\n\n> |c|\n> |-|\n> |d|\n",
+			want: "| a |\n| - |\n| b |\n\n> |c|\n> |-|\n> |d|\n",
+		},
+		{
+			src:  "> |c|\n> |-|\n> |d|\n\n|a|\n|-|\n|b|\n\n|e|\n|-|\n",
+			want: "> |c|\n> |-|\n> |d|\n\n| a |\n| - |\n| b |\n\n| e |\n| - |\n",
+		},
+	}
+
+	for _, tc := range testCases {
+		got, err := Format([]byte(tc.src))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+			t.Errorf("%q differs: (-want +got)\n%s", tc.src, diff)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package mdtt
+
+// snapshot is a copy of the editable state of a table.
+type snapshot struct {
+	// id identifies the state the snapshot was taken from.
+	id     int
+	cols   []column
+	rows   []row
+	cursor cursor
+}
+
+// history keeps the snapshots used by undo and redo.
+type history struct {
+	undo []snapshot
+	redo []snapshot
+	// state is the id of the current state, saved is the id of the
+	// state that was last written out.
+	state int
+	saved int
+	next  int
+}
+
+func (m *TableModel) snapshot() snapshot {
+	return snapshot{
+		id:     m.history.state,
+		cols:   copyColumns(m.cols),
+		rows:   copyRows(m.rows),
+		cursor: m.cursor,
+	}
+}
+
+// saveSnapshot records the current state before a mutation.
+// Any redo history is discarded.
+func (m *TableModel) saveSnapshot() {
+	m.history.undo = append(m.history.undo, m.snapshot())
+	m.history.redo = nil
+	m.history.next++
+	m.history.state = m.history.next
+}
+
+// dropSnapshotIfUnchanged removes the latest snapshot when the table is
+// identical to it, so that an insert session without edits leaves no
+// undo entry behind.
+func (m *TableModel) dropSnapshotIfUnchanged() {
+	n := len(m.history.undo)
+	if n == 0 {
+		return
+	}
+	if m.history.undo[n-1].equal(m.snapshot()) {
+		m.history.state = m.history.undo[n-1].id
+		m.history.undo = m.history.undo[:n-1]
+	}
+}
+
+func (m *TableModel) undo() {
+	n := len(m.history.undo)
+	if n == 0 {
+		return
+	}
+	m.history.redo = append(m.history.redo, m.snapshot())
+	m.restore(m.history.undo[n-1])
+	m.history.undo = m.history.undo[:n-1]
+}
+
+func (m *TableModel) redo() {
+	n := len(m.history.redo)
+	if n == 0 {
+		return
+	}
+	m.history.undo = append(m.history.undo, m.snapshot())
+	m.restore(m.history.redo[n-1])
+	m.history.redo = m.history.redo[:n-1]
+}
+
+// modified reports whether the table differs from the last written state.
+func (m TableModel) modified() bool {
+	return m.history.state != m.history.saved
+}
+
+// unedited reports whether the table is in the state it was parsed in, so
+// that its source can be written back as is.
+func (m TableModel) unedited() bool {
+	return m.source != "" && m.history.state == 0
+}
+
+// markSaved records the current state as written out.
+func (m *TableModel) markSaved() {
+	m.history.saved = m.history.state
+}
+
+func (m *TableModel) restore(s snapshot) {
+	m.history.state = s.id
+	m.cols = copyColumns(s.cols)
+	m.rows = copyRows(s.rows)
+	m.cursor.x = max(clamp(s.cursor.x, 0, len(m.cols)-1), 0)
+	m.cursor.y = max(clamp(s.cursor.y, 0, len(m.rows)-1), 0)
+
+	if len(m.rows) == 0 {
+		m.switchMode(HEADER)
+	}
+	m.SetHeight(len(m.rows))
+}
+
+func (s snapshot) equal(o snapshot) bool {
+	if len(s.cols) != len(o.cols) || len(s.rows) != len(o.rows) {
+		return false
+	}
+	for i, c := range s.cols {
+		if c.title.value() != o.cols[i].title.value() ||
+			c.alignment != o.cols[i].alignment {
+			return false
+		}
+	}
+	for i, r := range s.rows {
+		if len(r) != len(o.rows[i]) {
+			return false
+		}
+		for j, c := range r {
+			if c.value() != o.rows[i][j].value() {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func copyColumns(cols []column) []column {
+	cp := make([]column, len(cols))
+	for i, c := range cols {
+		cp[i] = column{
+			title:     NewCell(c.title.value()),
+			width:     c.width,
+			alignment: c.alignment,
+		}
+	}
+	return cp
+}
+
+func copyRows(rows []row) []row {
+	cp := make([]row, len(rows))
+	for i, r := range rows {
+		cp[i] = cloneRow(r)
+	}
+	return cp
+}
+
+func cloneRow(r row) row {
+	cp := make(row, len(r))
+	for i, c := range r {
+		cp[i] = NewCell(c.value())
+	}
+	return cp
+}
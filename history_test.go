@@ -0,0 +1,163 @@
+package mdtt
+
+import (
+	"errors"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestUndoRedo(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{
+			{title: NewCell("foo"), width: 5},
+			{title: NewCell("bar"), width: 5},
+		}),
+		WithNaiveRows([]naiveRow{
+			{"a", "b"},
+			{"c", "d"},
+		}),
+	)
+
+	m.clearCell(1)
+	m.deleteRows(1)
+
+	if len(m.rows) != 1 || m.rows[0][0].value() != "c" {
+		t.Fatalf("unexpected rows after delete: %d", len(m.rows))
+	}
+
+	m.undo()
+	if len(m.rows) != 2 || m.rows[0][0].value() != "" {
+		t.Errorf("undo delete: got %q", m.rows[0][0].value())
+	}
+
+	m.undo()
+	if m.rows[0][0].value() != "a" {
+		t.Errorf("undo clear: got %q, want %q", m.rows[0][0].value(), "a")
+	}
+
+	m.redo()
+	m.redo()
+	if len(m.rows) != 1 || m.rows[0][0].value() != "c" {
+		t.Errorf("redo: got %d rows", len(m.rows))
+	}
+
+	m.undo()
+	m.saveSnapshot()
+	m.redo()
+	if len(m.rows) != 2 {
+		t.Errorf("redo after a new edit should be a no-op")
+	}
+}
+
+func TestDropSnapshotIfUnchanged(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{{title: NewCell("foo"), width: 5}}),
+		WithNaiveRows([]naiveRow{{"a"}}),
+	)
+
+	m.saveSnapshot()
+	m.dropSnapshotIfUnchanged()
+	if len(m.history.undo) != 0 {
+		t.Errorf("got %d snapshots, want 0", len(m.history.undo))
+	}
+
+	m.saveSnapshot()
+	m.rows[0][0].setValue("b")
+	m.dropSnapshotIfUnchanged()
+	if len(m.history.undo) != 1 {
+		t.Errorf("got %d snapshots, want 1", len(m.history.undo))
+	}
+}
+
+func TestClearEmptyCells(t *testing.T) {
+	testCases := []struct {
+		name string
+		keys []tea.KeyMsg
+	}{
+		{name: "clear cell", keys: keys("x")},
+		{name: "delete block", keys: append([]tea.KeyMsg{{Type: tea.KeyCtrlV}}, keys("ld")...)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewTableModel(
+				WithColumns([]column{{title: NewCell("foo"), width: 5}, {title: NewCell("bar"), width: 5}}),
+				WithNaiveRows([]naiveRow{{"", ""}}),
+			)
+			for _, k := range tc.keys {
+				m, _ = m.Update(k)
+			}
+			if m.modified() || len(m.history.undo) != 0 {
+				t.Errorf("clearing empty cells leaves %d snapshots", len(m.history.undo))
+			}
+		})
+	}
+}
+
+func TestModified(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{{title: NewCell("foo"), width: 5}}),
+		WithNaiveRows([]naiveRow{{"a"}}),
+	)
+
+	if m.modified() {
+		t.Fatal("new table should not be modified")
+	}
+
+	m.clearCell(1)
+	if !m.modified() {
+		t.Error("table should be modified after clearCell")
+	}
+
+	m.undo()
+	if m.modified() {
+		t.Error("table should not be modified after undoing every edit")
+	}
+
+	m.redo()
+	m.markSaved()
+	if m.modified() {
+		t.Error("table should not be modified after markSaved")
+	}
+
+	m.undo()
+	if !m.modified() {
+		t.Error("table should be modified after undoing past the saved state")
+	}
+}
+
+func TestEditorUndo(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{{title: NewCell("foo"), width: 5}}),
+		WithNaiveRows([]naiveRow{{"a"}}),
+	)
+
+	// the snapshot waits for the editor
+	m, _ = m.Update(keys("I")[0])
+	if m.modified() || len(m.history.undo) != 0 {
+		t.Fatal("table should not be modified before the editor closes")
+	}
+
+	m, _ = m.Update(closeEditorMsg{err: errors.New("editor failed")})
+	if m.modified() || len(m.history.undo) != 0 {
+		t.Error("table should not be modified after the editor failed")
+	}
+	if m.message != "editor failed" {
+		t.Errorf("got message %q", m.message)
+	}
+
+	m, _ = m.Update(closeEditorMsg{text: "a"})
+	if m.modified() {
+		t.Error("table should not be modified by the same text")
+	}
+
+	m, _ = m.Update(closeEditorMsg{text: "b"})
+	if !m.modified() || m.rows[0][0].value() != "b" {
+		t.Fatalf("got %q, want %q", m.rows[0][0].value(), "b")
+	}
+	m.undo()
+	if m.modified() || m.rows[0][0].value() != "a" {
+		t.Errorf("undo: got %q, want %q", m.rows[0][0].value(), "a")
+	}
+}
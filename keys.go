@@ -0,0 +1,367 @@
+package mdtt
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// keyMap defines keybindings. It satisfies to the help.keyMap interface, which
+// is used to render the menu.
+//
+// The keys of a binding are key sequences, the keys of which are separated by
+// spaces, such as "d d". The space bar is called "space".
+type keyMap struct {
+	lineUp       key.Binding
+	lineDown     key.Binding
+	right        key.Binding
+	left         key.Binding
+	addRowCol    key.Binding
+	deleteRows   key.Binding
+	delete       key.Binding
+	yankRows     key.Binding
+	yank         key.Binding
+	yankCell     key.Binding
+	clearCell    key.Binding
+	paste        key.Binding
+	pageUp       key.Binding
+	pageDown     key.Binding
+	halfPageUp   key.Binding
+	halfPageDown key.Binding
+	gotoTop      key.Binding
+	gotoBottom   key.Binding
+	insertMode   key.Binding
+	normalMode   key.Binding
+	undo         key.Binding
+	redo         key.Binding
+	quit         key.Binding
+	writeQuit    key.Binding
+	discard      key.Binding
+	editor       key.Binding
+	command      key.Binding
+	search       key.Binding
+	searchBack   key.Binding
+	searchNext   key.Binding
+	searchPrev   key.Binding
+	picker       key.Binding
+	visual       key.Binding
+	visualLine   key.Binding
+	visualBlock  key.Binding
+	align        key.Binding
+	moveRowUp    key.Binding
+	moveRowDown  key.Binding
+	moveColLeft  key.Binding
+	moveColRight key.Binding
+	fill         key.Binding
+	replace      key.Binding
+	help         key.Binding
+}
+
+// defaultKeyMap returns a default set of keybindings.
+func defaultKeyMap() keyMap {
+	return keyMap{
+		lineUp:       newBinding("up", "up", "k"),
+		lineDown:     newBinding("down", "down", "j"),
+		right:        newBinding("right", "right", "l"),
+		left:         newBinding("left", "left", "h"),
+		addRowCol:    newBinding("add row/column", "o"),
+		deleteRows:   newBinding("delete row", "d d"),
+		delete:       newBinding("delete selection", "d"),
+		yankRows:     newBinding("copy row", "y y"),
+		yank:         newBinding("copy selection", "y"),
+		yankCell:     newBinding("copy cell", "y ."),
+		clearCell:    newBinding("clear cell", "x"),
+		paste:        newBinding("paste", "p"),
+		pageUp:       newBinding("page up", "b", "pgup"),
+		pageDown:     newBinding("page down", "f", "pgdown", "space"),
+		halfPageUp:   newBinding("½ page up", "ctrl+u"),
+		halfPageDown: newBinding("½ page down", "ctrl+d"),
+		gotoTop:      newBinding("go to start", "g", "home"),
+		gotoBottom:   newBinding("go to end", "G", "end"),
+		insertMode:   newBinding("insert mode", "i"),
+		normalMode:   newBinding("normal mode", "esc", "ctrl+c"),
+		undo:         newBinding("undo", "u"),
+		redo:         newBinding("redo", "ctrl+r"),
+		quit:         newBinding("quit", "q"),
+		writeQuit:    newBinding("write and quit", "Z Z"),
+		discard:      newBinding("quit without saving", "Z Q"),
+		editor:       newBinding("open editor", "I"),
+		command:      newBinding("command line", ":"),
+		search:       newBinding("search", "/"),
+		searchBack:   newBinding("search backward", "#"),
+		searchNext:   newBinding("next match", "n"),
+		searchPrev:   newBinding("previous match", "N"),
+		picker:       newBinding("select table", "T"),
+		visual:       newBinding("select columns", "v"),
+		visualLine:   newBinding("select rows", "V"),
+		visualBlock:  newBinding("select block", "ctrl+v"),
+		align:        newBinding("cycle alignment", "="),
+		moveRowUp:    newBinding("move row up", "alt+k", "alt+up"),
+		moveRowDown:  newBinding("move row down", "alt+j", "alt+down"),
+		moveColLeft:  newBinding("move column left", "alt+h", "alt+left"),
+		moveColRight: newBinding("move column right", "alt+l", "alt+right"),
+		fill:         newBinding("fill down selection", "F"),
+		replace:      newBinding("replace selection", "r"),
+		help:         newBinding("help", "?"),
+	}
+}
+
+func (k keyMap) ShortHelp() []key.Binding {
+	return []key.Binding{k.help}
+}
+
+func (k keyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{
+		{k.lineUp, k.lineDown, k.left, k.right, k.pageUp, k.pageDown,
+			k.halfPageUp, k.halfPageDown, k.gotoTop, k.gotoBottom,
+			k.search, k.searchBack, k.searchNext, k.searchPrev,
+			k.visual, k.visualLine, k.visualBlock, k.yank, k.delete, k.fill, k.replace},
+		{k.insertMode, k.editor, k.normalMode, k.addRowCol, k.deleteRows,
+			k.yankRows, k.yankCell, k.clearCell, k.paste, k.align,
+			k.moveRowUp, k.moveRowDown, k.moveColLeft, k.moveColRight,
+			k.undo, k.redo, k.command, k.picker, k.quit, k.writeQuit, k.discard, k.help},
+	}
+}
+
+// newBinding binds the key sequences to an action described by desc in the
+// help.
+func newBinding(desc string, keys ...string) key.Binding {
+	return key.NewBinding(
+		key.WithKeys(keys...),
+		key.WithHelp(helpKeys(keys), desc),
+	)
+}
+
+// helpKeyNames are the names of the keys shown in the help in place of their
+// own.
+var helpKeyNames = map[string]string{
+	"up":    "↑",
+	"down":  "↓",
+	"left":  "←",
+	"right": "→",
+}
+
+// helpKeys returns the key sequences as shown in the help, such as "↑/k" or
+// "dd".
+func helpKeys(keys []string) string {
+	seqs := make([]string, len(keys))
+	for i, k := range keys {
+		names := strings.Fields(k)
+		sep := ""
+		for j, n := range names {
+			if h, ok := helpKeyNames[n]; ok {
+				names[j] = h
+			}
+			if utf8.RuneCountInString(names[j]) > 1 {
+				sep = " "
+			}
+		}
+		seqs[i] = strings.Join(names, sep)
+	}
+	return strings.Join(seqs, "/")
+}
+
+// Scopes in which actions are bound. The key sequences bound in a scope must
+// not conflict with each other.
+const (
+	normalScope = 1 << iota
+	visualScope
+	helpScope
+)
+
+// action is a binding of the key map with the name it has in the config
+// file.
+type action struct {
+	name    string
+	binding *key.Binding
+	scopes  int
+}
+
+// actions returns the actions of the key map.
+func (k *keyMap) actions() []action {
+	return []action{
+		{"up", &k.lineUp, normalScope | visualScope},
+		{"down", &k.lineDown, normalScope | visualScope},
+		{"left", &k.left, normalScope | visualScope},
+		{"right", &k.right, normalScope | visualScope},
+		{"page_up", &k.pageUp, normalScope | visualScope},
+		{"page_down", &k.pageDown, normalScope | visualScope},
+		{"half_page_up", &k.halfPageUp, normalScope | visualScope},
+		{"half_page_down", &k.halfPageDown, normalScope | visualScope},
+		{"top", &k.gotoTop, normalScope | visualScope},
+		{"bottom", &k.gotoBottom, normalScope | visualScope},
+		{"insert", &k.insertMode, normalScope},
+		{"editor", &k.editor, normalScope},
+		{"normal_mode", &k.normalMode, normalScope | visualScope | helpScope},
+		{"add", &k.addRowCol, normalScope | visualScope},
+		{"delete_rows", &k.deleteRows, normalScope},
+		{"delete", &k.delete, visualScope},
+		{"yank_rows", &k.yankRows, normalScope},
+		{"yank_cell", &k.yankCell, normalScope},
+		{"yank", &k.yank, visualScope},
+		{"clear", &k.clearCell, normalScope | visualScope},
+		{"paste", &k.paste, normalScope | visualScope},
+		{"align", &k.align, normalScope | visualScope},
+		{"move_row_up", &k.moveRowUp, normalScope | visualScope},
+		{"move_row_down", &k.moveRowDown, normalScope | visualScope},
+		{"move_column_left", &k.moveColLeft, normalScope | visualScope},
+		{"move_column_right", &k.moveColRight, normalScope | visualScope},
+		{"undo", &k.undo, normalScope},
+		{"redo", &k.redo, normalScope},
+		{"command", &k.command, normalScope | visualScope},
+		{"search", &k.search, normalScope},
+		{"search_backward", &k.searchBack, normalScope},
+		{"next_match", &k.searchNext, normalScope},
+		{"previous_match", &k.searchPrev, normalScope},
+		{"select_table", &k.picker, normalScope},
+		{"select_columns", &k.visual, normalScope | visualScope},
+		{"select_rows", &k.visualLine, normalScope | visualScope},
+		{"select_block", &k.visualBlock, normalScope | visualScope},
+		{"fill", &k.fill, visualScope},
+		{"replace", &k.replace, visualScope},
+		{"quit", &k.quit, normalScope | helpScope},
+		{"write_quit", &k.writeQuit, normalScope},
+		{"discard", &k.discard, normalScope},
+		{"help", &k.help, normalScope | helpScope},
+	}
+}
+
+// bindings returns the bindings of the actions bound in scope.
+func (k keyMap) bindings(scope int) []key.Binding {
+	var bs []key.Binding
+	for _, a := range k.actions() {
+		if a.scopes&scope != 0 {
+			bs = append(bs, *a.binding)
+		}
+	}
+	return bs
+}
+
+// bind binds the action name to the key sequences, replacing its default
+// ones. No sequences unbind the action.
+func (k *keyMap) bind(name string, seqs []string) error {
+	i := slices.IndexFunc(k.actions(), func(a action) bool { return a.name == name })
+	if i < 0 {
+		return fmt.Errorf("unknown action %q", name)
+	}
+	b := k.actions()[i].binding
+	if len(seqs) == 0 {
+		b.Unbind()
+		return nil
+	}
+
+	keys := make([]string, len(seqs))
+	for j, s := range seqs {
+		names := strings.Fields(s)
+		if len(names) == 0 {
+			return fmt.Errorf("empty key sequence for %s", name)
+		}
+		for _, n := range names {
+			if !validKey(n) {
+				return fmt.Errorf("invalid key %q for %s", n, name)
+			}
+		}
+		keys[j] = strings.Join(names, " ")
+	}
+	b.SetKeys(keys...)
+	b.SetHelp(helpKeys(keys), b.Help().Desc)
+	return nil
+}
+
+// validate reports key sequences that are bound to several actions of a
+// scope, or that begin another one so that it could never be typed. Digits
+// are taken by counts, and the keys leaving insert mode can not be
+// sequences since the cell takes any other key.
+func (k keyMap) validate() error {
+	for _, s := range k.normalMode.Keys() {
+		if strings.Contains(s, " ") {
+			return fmt.Errorf("normal_mode: %q is a key sequence, it must be a single key", s)
+		}
+	}
+
+	type seq struct {
+		keys   string
+		action string
+	}
+	for _, scope := range []int{normalScope, visualScope, helpScope} {
+		var seqs []seq
+		for _, a := range k.actions() {
+			if a.scopes&scope == 0 {
+				continue
+			}
+			for _, s := range a.binding.Keys() {
+				seqs = append(seqs, seq{s, a.name})
+			}
+		}
+
+		for i, a := range seqs {
+			for _, n := range strings.Fields(a.keys) {
+				if len(n) == 1 && '1' <= n[0] && n[0] <= '9' {
+					return fmt.Errorf("%s: key %q is taken by counts", a.action, n)
+				}
+			}
+			for _, b := range seqs[i+1:] {
+				switch {
+				case a.keys == b.keys && a.action == b.action:
+					continue
+				case a.keys == b.keys:
+					return fmt.Errorf("%q is bound to both %s and %s", a.keys, a.action, b.action)
+				case strings.HasPrefix(b.keys, a.keys+" "):
+					return fmt.Errorf("%q of %s begins %q of %s", a.keys, a.action, b.keys, b.action)
+				case strings.HasPrefix(a.keys, b.keys+" "):
+					return fmt.Errorf("%q of %s begins %q of %s", b.keys, b.action, a.keys, a.action)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// keyNames are the names of the keys other than characters.
+var keyNames = func() map[string]bool {
+	names := map[string]bool{"space": true}
+	for t := tea.KeyF20; t <= tea.KeyCtrlQuestionMark; t++ {
+		if s := t.String(); s != "" && s != " " {
+			names[s] = true
+		}
+	}
+	return names
+}()
+
+// validKey reports whether name is the name of a key, such as "j", "ctrl+d",
+// "alt+up" or "f1".
+func validKey(name string) bool {
+	name = strings.TrimPrefix(name, "alt+")
+	if keyNames[name] {
+		return true
+	}
+	r, size := utf8.DecodeRuneInString(name)
+	return size == len(name) && r != utf8.RuneError && unicode.IsGraphic(r) && !unicode.IsSpace(r)
+}
+
+// keyName returns the name of the key of msg in key sequences, which is the
+// name bubbletea gives it except for the space bar.
+func keyName(msg tea.KeyMsg) string {
+	s := msg.String()
+	if strings.HasSuffix(s, " ") {
+		s = strings.TrimSuffix(s, " ") + "space"
+	}
+	return s
+}
+
+// keyMatches reports whether the key of msg is bound to any of the bindings.
+func keyMatches(msg tea.KeyMsg, bindings ...key.Binding) bool {
+	name := keyName(msg)
+	for _, b := range bindings {
+		if b.Enabled() && slices.Contains(b.Keys(), name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,213 @@
+package mdtt
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Problem is a problem of a table found by Lint. Line and Col count from 1,
+// Col in bytes.
+type Problem struct {
+	Line int
+	Col  int
+	Msg  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Col, p.Msg)
+}
+
+// lintCell is a cell of a table line and the byte offset it starts at.
+type lintCell struct {
+	text string
+	col  int
+}
+
+var tableDelimCell = regexp.MustCompile(`^:?-+:?$`)
+
+// Lint reports the problems of the tables of the markdown in src: rows with
+// more or fewer cells than the header, unescaped pipes in code spans,
+// delimiter rows that do not match the header, empty and duplicated column
+// names, and trailing whitespace. The problems are sorted by position.
+func Lint(src []byte) []Problem {
+	lines := strings.Split(string(src), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimSuffix(lines[i], "\r")
+	}
+
+	var problems []Problem
+	tl := tableLocator{}
+	for i := 0; i < len(lines); i++ {
+		if tl.isCodeFence(lines[i]) {
+			tl.inCodeBlock = !tl.inCodeBlock
+			tl.codeFence = trimSpace(lines[i])
+		}
+		if tl.inCodeBlock || i+1 >= len(lines) || !isLintTable(lines[i], lines[i+1]) {
+			continue
+		}
+
+		end := i + 2
+		for end < len(lines) && !isBlankLine(lines[end]) && !isThematicBreak(lines[end]) {
+			end++
+		}
+		problems = append(problems, lintTable(lines[i:end], i+1)...)
+		i = end - 1
+	}
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		if problems[i].Line != problems[j].Line {
+			return problems[i].Line < problems[j].Line
+		}
+		return problems[i].Col < problems[j].Col
+	})
+	return problems
+}
+
+// isLintTable reports whether a table starts with the lines header and delim.
+// Unlike isTableHeader, the number of cells may differ, which is reported.
+func isLintTable(header, delim string) bool {
+	if isBlankLine(header) || isCodeIndent(header) || !isTableDelimiter(delim) {
+		return false
+	}
+	return strings.Contains(header, "|") || strings.Contains(delim, "|")
+}
+
+// lintTable reports the problems of the table made of lines, the first of
+// which is the line numbered first.
+func lintTable(lines []string, first int) []Problem {
+	var problems []Problem
+	report := func(i, col int, format string, a ...any) {
+		problems = append(problems, Problem{
+			Line: first + i,
+			Col:  col + 1,
+			Msg:  fmt.Sprintf(format, a...),
+		})
+	}
+
+	header := splitCells(lines[0])
+	seen := make(map[string]bool)
+	for _, c := range header {
+		name := trimSpace(c.text)
+		if name == "" {
+			report(0, c.col, "empty header cell")
+		} else if seen[name] {
+			report(0, c.col, "duplicate column name %q", name)
+		}
+		seen[name] = true
+	}
+
+	delim := splitCells(lines[1])
+	if len(delim) != len(header) {
+		report(1, 0, "delimiter row has %s, the header has %d", cellCount(len(delim)), len(header))
+	}
+	for _, c := range delim {
+		if !tableDelimCell.MatchString(trimSpace(c.text)) {
+			report(1, c.col, "invalid delimiter cell %q", trimSpace(c.text))
+		}
+	}
+
+	for i, line := range lines {
+		cells := splitCells(line)
+		if i > 1 && len(cells) != len(header) {
+			col := len(strings.TrimRight(line, " \t"))
+			if len(cells) > len(header) {
+				col = cells[len(header)].col
+			}
+			report(i, col, "row has %s, the header has %d", cellCount(len(cells)), len(header))
+		}
+		for _, col := range codeSpanPipes(line) {
+			report(i, col, "unescaped pipe in code span")
+		}
+		if trimmed := strings.TrimRight(line, " \t"); len(trimmed) < len(line) {
+			report(i, len(trimmed), "trailing whitespace")
+		}
+	}
+	return problems
+}
+
+func cellCount(n int) string {
+	if n == 1 {
+		return "1 cell"
+	}
+	return fmt.Sprintf("%d cells", n)
+}
+
+// splitCells splits a table line at the pipes that are not escaped. The
+// leading and trailing pipes are optional.
+func splitCells(line string) []lintCell {
+	start := len(line) - len(strings.TrimLeft(line, " \t"))
+	if strings.HasPrefix(line[start:], "|") {
+		start++
+	}
+
+	var cells []lintCell
+	for i := start; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '|':
+			cells = append(cells, lintCell{line[start:i], start})
+			start = i + 1
+		}
+	}
+	if rest := line[min(start, len(line)):]; trimSpace(rest) != "" || len(cells) == 0 {
+		cells = append(cells, lintCell{rest, start})
+	}
+	return cells
+}
+
+// codeSpanPipes returns the offsets of the unescaped pipes in the code spans
+// of line. They split the cell even in a code span.
+func codeSpanPipes(line string) []int {
+	var pipes []int
+	for i := 0; i < len(line); {
+		if line[i] == '\\' {
+			i += 2
+			continue
+		}
+		if line[i] != '`' {
+			i++
+			continue
+		}
+
+		n := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+		fence := line[i : i+n]
+		end := closingBackticks(line, i+n, fence)
+		if end < 0 {
+			i += n
+			continue
+		}
+		for j := i + n; j < end; j++ {
+			if line[j] == '\\' {
+				j++
+			} else if line[j] == '|' {
+				pipes = append(pipes, j)
+			}
+		}
+		i = end + n
+	}
+	return pipes
+}
+
+// closingBackticks returns the offset of the run of backticks equal to fence
+// that closes the code span starting at i, or -1.
+func closingBackticks(line string, i int, fence string) int {
+	for i < len(line) {
+		j := strings.Index(line[i:], fence)
+		if j < 0 {
+			return -1
+		}
+		j += i
+		end := j + len(fence)
+		if end == len(line) || line[end] != '`' {
+			return j
+		}
+		i = end
+		for i < len(line) && line[i] == '`' {
+			i++
+		}
+	}
+	return -1
+}
@@ -0,0 +1,97 @@
+package mdtt
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLint(t *testing.T) {
+	testCases := []struct {
+		name string
+		src  string
+		want []Problem
+	}{
+		{
+			name: "valid",
+			src:  "# Title\n\n| a | b \\| c |\n| - | :-: |\n| `x \\| y` | z |\n",
+			want: nil,
+		},
+		{
+			name: "ragged rows",
+			src:  "| a | b |\n| - | - |\n| 1 |\n| 1 | 2 | 3 |\n",
+			want: []Problem{
+				{Line: 3, Col: 6, Msg: "row has 1 cell, the header has 2"},
+				{Line: 4, Col: 10, Msg: "row has 3 cells, the header has 2"},
+			},
+		},
+		{
+			name: "pipe in code span",
+			src:  "| a | b |\n| - | - |\n| `x|y` | z |\n",
+			want: []Problem{
+				{Line: 3, Col: 5, Msg: "unescaped pipe in code span"},
+				{Line: 3, Col: 10, Msg: "row has 3 cells, the header has 2"},
+			},
+		},
+		{
+			name: "delimiter row",
+			src:  "| a | b |\n| - | x | - |\n",
+			want: []Problem{
+				{Line: 2, Col: 1, Msg: "delimiter row has 3 cells, the header has 2"},
+				{Line: 2, Col: 6, Msg: `invalid delimiter cell "x"`},
+			},
+		},
+		{
+			name: "header",
+			src:  "| a |  | a |\n| - | - | - |\n",
+			want: []Problem{
+				{Line: 1, Col: 6, Msg: "empty header cell"},
+				{Line: 1, Col: 9, Msg: `duplicate column name "a"`},
+			},
+		},
+		{
+			name: "trailing whitespace",
+			src:  "text  \n\n| a | b |  \n| - | - |\n| 1 | 2 |\t\n",
+			want: []Problem{
+				{Line: 3, Col: 10, Msg: "trailing whitespace"},
+				{Line: 5, Col: 10, Msg: "trailing whitespace"},
+			},
+		},
+		{
+			name: "code block",
+			src:  "```\n| a |\n| - | - |\n```\n",
+			want: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := Lint([]byte(tc.src))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSplitCells(t *testing.T) {
+	testCases := []struct {
+		line string
+		want []string
+	}{
+		{"| a | b |", []string{" a ", " b "}},
+		{"a | b", []string{"a ", " b"}},
+		{"  | a \\| b |  ", []string{" a \\| b "}},
+		{"| a |  |", []string{" a ", "  "}},
+	}
+
+	for _, tc := range testCases {
+		var got []string
+		for _, c := range splitCells(tc.line) {
+			got = append(got, c.text)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("%q differs: (-want +got)\n%s", tc.line, diff)
+		}
+	}
+}
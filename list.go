@@ -60,12 +60,17 @@ func (m headerList) update(msg tea.Msg) (headerList, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.list.SetWidth(msg.Width)
+		// the list starts with a blank line
+		m.list.SetHeight(min(len(m.list.Items())*2, msg.Height-1))
 		return m, nil
 
 	case tea.KeyMsg:
 		switch keypress := msg.String(); keypress {
 		case "enter":
 			return m, selectCmd(m.list.Index())
+		case "q", "esc", "ctrl+c":
+			// the model asks whether to save the modified tables
+			return m, quitCmd()
 		}
 	}
 
@@ -91,6 +96,9 @@ func NewHeaderList(opts ...func(*headerList)) headerList {
 	l.SetShowTitle(false)
 	l.Styles.PaginationStyle = listPaginationStyle
 	l.Styles.HelpStyle = listHelpStyle
+	// quitting goes through quitMsg instead of quitting at once
+	l.KeyMap.Quit.SetEnabled(false)
+	l.KeyMap.ForceQuit.SetEnabled(false)
 
 	m := headerList{list: l}
 
@@ -120,6 +128,9 @@ func WithTables(tables []TableModel) func(*headerList) {
 			}
 			header := lipgloss.JoinHorizontal(lipgloss.Left, headerCells...)
 			header = padOrTruncate(header, headerWidth) + " …"
+			if t.modified() {
+				header += " [+]"
+			}
 			items = append(items, item(listHeaderStyle.Render(header)))
 		}
 		m.list.SetItems(items)
@@ -0,0 +1,43 @@
+package mdtt
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestPickerQuit(t *testing.T) {
+	src := "| a |\n| - |\n| 1 |\n\n| b |\n| - |\n| 2 |\n"
+
+	for _, k := range []tea.KeyMsg{
+		{Type: tea.KeyRunes, Runes: []rune{'q'}},
+		{Type: tea.KeyEsc},
+		{Type: tea.KeyCtrlC},
+	} {
+		t.Run(k.String(), func(t *testing.T) {
+			m, err := NewUI(WithMarkdown([]byte(src)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tm tea.Model = m
+			tm, _ = tm.Update(selectMsg{idx: 0})
+			for _, k := range keys("x") {
+				tm, _ = tm.Update(k)
+			}
+			tm, _ = tm.Update(pickerMsg{})
+
+			tm, cmd := tm.Update(k)
+			if cmd == nil {
+				t.Fatal("no command")
+			}
+			msg := cmd()
+			if _, ok := msg.(quitMsg); !ok {
+				t.Fatalf("got %T, want quitMsg", msg)
+			}
+			tm, _ = tm.Update(msg)
+			if !tm.(Model).confirmQuit {
+				t.Errorf("quit without asking to save the modified table")
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package mdtt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// moveRange moves s[i0:i1+1] by n places and returns the new slice.
+func moveRange[T any](s []T, i0, i1, n int) []T {
+	block := append([]T(nil), s[i0:i1+1]...)
+	rest := append(append([]T(nil), s[:i0]...), s[i1+1:]...)
+	to := i0 + n
+
+	moved := make([]T, 0, len(s))
+	moved = append(moved, rest[:to]...)
+	moved = append(moved, block...)
+	return append(moved, rest[to:]...)
+}
+
+// moveRows moves the rows y0 to y1 by n rows, as far as the table allows. It
+// returns the number of rows they moved by.
+func (m *TableModel) moveRows(y0, y1, n int) int {
+	n = clamp(n, -y0, len(m.rows)-1-y1)
+	if n == 0 {
+		return 0
+	}
+	m.saveSnapshot()
+	m.SetRows(moveRange(m.rows, y0, y1, n))
+	return n
+}
+
+// moveColumns moves the columns x0 to x1 by n columns, as far as the table
+// allows. The columns keep their width and alignment. It returns the number
+// of columns they moved by.
+func (m *TableModel) moveColumns(x0, x1, n int) int {
+	n = clamp(n, -x0, len(m.cols)-1-x1)
+	if n == 0 {
+		return 0
+	}
+	m.saveSnapshot()
+	m.SetColumns(moveRange(m.cols, x0, x1, n))
+	for i := range m.rows {
+		m.rows[i] = moveRange(m.rows[i], x0, x1, n)
+	}
+	return n
+}
+
+// moveRow moves the current row, or the rows of the selection, by n rows.
+// The cursor and the selection follow them.
+func (m *TableModel) moveRow(n int) {
+	if len(m.rows) == 0 || m.mode == HEADER || (isVisual(m.mode) && m.visual.header) {
+		return
+	}
+
+	y0, y1 := m.cursor.y, m.cursor.y
+	if isVisual(m.mode) {
+		_, y0, _, y1 = m.selectionRect()
+	}
+	n = m.moveRows(y0, y1, n)
+	m.visual.anchor.y += n
+	m.gotoRow(m.cursor.y + n + 1)
+}
+
+// moveColumn moves the current column, or the columns of the selection, by n
+// columns. The cursor and the selection follow them.
+func (m *TableModel) moveColumn(n int) {
+	if len(m.cols) == 0 {
+		return
+	}
+
+	x0, x1 := m.cursor.x, m.cursor.x
+	if isVisual(m.mode) {
+		x0, _, x1, _ = m.selectionRect()
+	}
+	n = m.moveColumns(x0, x1, n)
+	m.visual.anchor.x += n
+	m.cursor.x += n
+	m.updateViewport()
+}
+
+// cmdMove moves the current row, or the rows of the '<,'> range, below the
+// row given by its argument, 0 being the top of the table. In the header, or
+// for a column-wise selection, it moves columns after the column given by
+// title or index instead. "+n" and "-n" move by n rows or columns.
+func cmdMove(m *TableModel, c exCommand) (tea.Cmd, error) {
+	if c.args == "" {
+		return nil, fmt.Errorf("usage: move {row|column}")
+	}
+
+	visual := c.rng == "'<,'>"
+	columns := m.mode == HEADER
+	x0, x1, y0, y1 := m.cursor.x, m.cursor.x, m.cursor.y, m.cursor.y
+	if visual {
+		s := m.lastVisual
+		columns = s.mode == VISUAL_COLUMN
+		x0, y0, x1, y1 = s.rect(s.cursor, len(m.rows), len(m.cols))
+	}
+	if !columns && len(m.rows) == 0 {
+		return nil, fmt.Errorf("E16: Invalid range")
+	}
+
+	lo, hi := y0, y1
+	if columns {
+		lo, hi = x0, x1
+	}
+	n, err := m.parseMoveTarget(c.args, lo, hi, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cursor goes to the first moved row or column
+	if columns {
+		n = m.moveColumns(x0, x1, n)
+		m.cursor.x = x0 + n
+	} else {
+		n = m.moveRows(y0, y1, n)
+		m.gotoRow(y0 + n + 1)
+	}
+	if visual {
+		if columns {
+			m.lastVisual.anchor.x += n
+			m.lastVisual.cursor.x += n
+		} else {
+			m.lastVisual.anchor.y += n
+			m.lastVisual.cursor.y += n
+		}
+	}
+	m.updateViewport()
+	return nil, nil
+}
+
+// parseMoveTarget returns how far the items lo to hi move for the argument of
+// :move.
+func (m TableModel) parseMoveTarget(arg string, lo, hi int, columns bool) (int, error) {
+	if strings.HasPrefix(arg, "+") || strings.HasPrefix(arg, "-") {
+		n, err := strconv.Atoi(arg)
+		if err != nil {
+			return 0, fmt.Errorf("E14: Invalid address: %s", arg)
+		}
+		return n, nil
+	}
+
+	// dest is the number of items the range ends up after
+	var dest int
+	if columns && arg != "0" {
+		x, err := m.findColumn(arg)
+		if err != nil {
+			return 0, err
+		}
+		dest = x + 1
+	} else {
+		var err error
+		dest, err = strconv.Atoi(arg)
+		if err != nil || dest < 0 {
+			return 0, fmt.Errorf("E14: Invalid address: %s", arg)
+		}
+	}
+
+	switch {
+	case dest <= lo:
+		return dest - lo, nil
+	case dest > hi:
+		return dest - hi - 1, nil
+	}
+	return 0, fmt.Errorf("E134: Cannot move a range into itself")
+}
@@ -0,0 +1,192 @@
+package mdtt
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMoveRange(t *testing.T) {
+	testCases := []struct {
+		i0, i1, n int
+		want      []int
+	}{
+		{i0: 0, i1: 0, n: 1, want: []int{1, 0, 2, 3, 4}},
+		{i0: 1, i1: 2, n: 2, want: []int{0, 3, 4, 1, 2}},
+		{i0: 3, i1: 4, n: -3, want: []int{3, 4, 0, 1, 2}},
+		{i0: 2, i1: 2, n: 0, want: []int{0, 1, 2, 3, 4}},
+	}
+
+	for _, tc := range testCases {
+		got := moveRange([]int{0, 1, 2, 3, 4}, tc.i0, tc.i1, tc.n)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("moveRange(%d, %d, %d) differs: (-want +got)\n%s", tc.i0, tc.i1, tc.n, diff)
+		}
+	}
+}
+
+func TestMove(t *testing.T) {
+	newTable := func() TableModel {
+		return NewTableModel(
+			WithColumns([]column{
+				{title: NewCell("a"), width: 3, alignment: "left"},
+				{title: NewCell("b"), width: 4, alignment: "right"},
+				{title: NewCell("c"), width: 5, alignment: "center"},
+			}),
+			WithNaiveRows([]naiveRow{
+				{"1", "2", "3"},
+				{"4", "5", "6"},
+				{"7", "8", "9"},
+			}),
+		)
+	}
+	alt := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s), Alt: true}
+	}
+	enter := tea.KeyMsg{Type: tea.KeyEnter}
+
+	testCases := []struct {
+		name   string
+		keys   []tea.KeyMsg
+		want   [][]string
+		titles string
+		cursor cursor
+	}{
+		{
+			name:   "row down",
+			keys:   []tea.KeyMsg{alt("j")},
+			want:   [][]string{{"4", "5", "6"}, {"1", "2", "3"}, {"7", "8", "9"}},
+			titles: "abc",
+			cursor: cursor{y: 1},
+		},
+		{
+			name:   "row down with count",
+			keys:   append(keys("5"), alt("j")),
+			want:   [][]string{{"4", "5", "6"}, {"7", "8", "9"}, {"1", "2", "3"}},
+			titles: "abc",
+			cursor: cursor{y: 2},
+		},
+		{
+			name:   "column right",
+			keys:   []tea.KeyMsg{alt("l"), alt("l")},
+			want:   [][]string{{"2", "3", "1"}, {"5", "6", "4"}, {"8", "9", "7"}},
+			titles: "bca",
+			cursor: cursor{x: 2},
+		},
+		{
+			name:   "column left from the header",
+			keys:   append(keys("llk"), alt("h")),
+			want:   [][]string{{"1", "3", "2"}, {"4", "6", "5"}, {"7", "9", "8"}},
+			titles: "acb",
+			cursor: cursor{x: 1},
+		},
+		{
+			name:   "selected rows",
+			keys:   append(keys("Vj"), alt("j"), tea.KeyMsg{Type: tea.KeyEsc}),
+			want:   [][]string{{"7", "8", "9"}, {"1", "2", "3"}, {"4", "5", "6"}},
+			titles: "abc",
+			cursor: cursor{y: 2},
+		},
+		{
+			name:   "command",
+			keys:   append(keys(":m 3"), enter),
+			want:   [][]string{{"4", "5", "6"}, {"7", "8", "9"}, {"1", "2", "3"}},
+			titles: "abc",
+			cursor: cursor{y: 2},
+		},
+		{
+			name:   "command to the top",
+			keys:   append(keys("G:m0"), enter),
+			want:   [][]string{{"7", "8", "9"}, {"1", "2", "3"}, {"4", "5", "6"}},
+			titles: "abc",
+		},
+		{
+			name:   "command with selected columns",
+			keys:   append(keys("vl:m c"), enter),
+			want:   [][]string{{"3", "1", "2"}, {"6", "4", "5"}, {"9", "7", "8"}},
+			titles: "cab",
+			cursor: cursor{x: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTable()
+			for _, k := range tc.keys {
+				m, _ = m.Update(k)
+			}
+			if m.messageErr {
+				t.Fatal(m.message)
+			}
+			if diff := cmp.Diff(tc.want, values(m.rows)); diff != "" {
+				t.Errorf("rows differ: (-want +got)\n%s", diff)
+			}
+			var titles string
+			for _, c := range m.cols {
+				titles += c.title.value()
+			}
+			if titles != tc.titles {
+				t.Errorf("got titles %q, want %q", titles, tc.titles)
+			}
+			if m.cursor != tc.cursor {
+				t.Errorf("got cursor %+v, want %+v", m.cursor, tc.cursor)
+			}
+		})
+	}
+}
+
+func TestMoveColumnKeepsWidthAndAlignment(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{
+			{title: NewCell("a"), width: 3, alignment: "left"},
+			{title: NewCell("b"), width: 7, alignment: "right"},
+		}),
+		WithNaiveRows([]naiveRow{{"1", "2"}}),
+	)
+	m.moveColumn(1)
+
+	want := []column{
+		{title: NewCell("b"), width: 7, alignment: "right"},
+		{title: NewCell("a"), width: 3, alignment: "left"},
+	}
+	for i, c := range m.cols {
+		if c.title.value() != want[i].title.value() || c.width != want[i].width ||
+			c.alignment != want[i].alignment {
+			t.Errorf("column %d: got %q width %d %s", i, c.title.value(), c.width, c.alignment)
+		}
+	}
+}
+
+func TestParseMoveTarget(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{{title: NewCell("a"), width: 3}}),
+		WithNaiveRows([]naiveRow{{"1"}, {"2"}, {"3"}, {"4"}, {"5"}}),
+	)
+
+	testCases := []struct {
+		arg     string
+		lo, hi  int
+		want    int
+		wantErr bool
+	}{
+		{arg: "0", lo: 2, hi: 2, want: -2},
+		{arg: "5", lo: 2, hi: 2, want: 2},
+		{arg: "2", lo: 2, hi: 2, want: 0},
+		{arg: "3", lo: 2, hi: 2, want: 0},
+		{arg: "+1", lo: 2, hi: 2, want: 1},
+		{arg: "-2", lo: 2, hi: 2, want: -2},
+		{arg: "3", lo: 1, hi: 3, wantErr: true},
+		{arg: "x", lo: 1, hi: 1, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		got, err := m.parseMoveTarget(tc.arg, tc.lo, tc.hi, false)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: unexpected error %v", tc.arg, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %d, want %d", tc.arg, got, tc.want)
+		}
+	}
+}
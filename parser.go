@@ -17,9 +17,13 @@ var highPriority = 100
 // tableModelBuilder build TableModel from markdown
 type tableModelBuilder struct {
 	inTable bool
-	buf     *bytes.Buffer
+	// source offsets of the first and the last cell of the current table
+	start int
+	stop  int
 	// temporary storage of table rows
-	rows []string
+	rows [][]string
+	// temporary storage of the cells of the current row
+	row []string
 	// temporary storage of table columns
 	cols []string
 	// temporary storage of table alignments
@@ -28,9 +32,13 @@ type tableModelBuilder struct {
 }
 
 type table struct {
-	rows      []string
+	rows      [][]string
 	cols      []string
 	alignment []string
+	// source is the markdown text of the table and offset its position in
+	// the markdown
+	source string
+	offset int
 }
 
 func parse(s []byte) []TableModel {
@@ -60,18 +68,31 @@ func (b *tableModelBuilder) build() []TableModel {
 
 	var models []TableModel
 	for _, t := range b.tables {
+		ncols := len(t.cols)
+		for _, r := range t.rows {
+			ncols = max(len(r), ncols)
+		}
+		// overflow cells are kept in columns without a title
+		for len(t.cols) < ncols {
+			t.cols = append(t.cols, "")
+			t.alignment = append(t.alignment, "none")
+		}
+
 		var rows []naiveRow
-		for i := range len(t.rows) / len(t.cols) {
-			rows = append(rows, t.rows[i*len(t.cols):(i+1)*len(t.cols)])
+		for _, r := range t.rows {
+			for len(r) < ncols {
+				r = append(r, "")
+			}
+			rows = append(rows, r)
 		}
 
 		var cols []column
 		for i := range len(t.cols) {
 			var maxWidth int
 			for _, r := range rows {
-				maxWidth = max(len(r[i]), maxWidth)
+				maxWidth = max(cellWidth(r[i]), maxWidth)
 			}
-			maxWidth = max(maxWidth, len(t.cols[i]))
+			maxWidth = max(maxWidth, cellWidth(t.cols[i]))
 			cols = append(cols, column{
 				title:     NewCell(t.cols[i]),
 				width:     maxWidth + 2,
@@ -79,6 +100,7 @@ func (b *tableModelBuilder) build() []TableModel {
 			})
 		}
 
+		source, offset := t.source, t.offset
 		t := NewTableModel(
 			WithColumns(cols),
 			WithNaiveRows(rows),
@@ -89,13 +111,15 @@ func (b *tableModelBuilder) build() []TableModel {
 		style := defaultStyles()
 
 		t.SetStyles(style)
+		t.source = source
+		t.offset = offset
 		models = append(models, t)
 	}
 	return models
 }
 
 func NewTableModelBuilder() *tableModelBuilder {
-	return &tableModelBuilder{buf: bytes.NewBuffer(nil)}
+	return &tableModelBuilder{}
 }
 
 // RegisterFuncs implements NodeRenderer.RegisterFuncs.
@@ -154,40 +178,110 @@ func (tb *tableModelBuilder) consumeNode(w util.BufWriter, source []byte, node a
 	if entering {
 		if node.Kind() == astext.KindTable {
 			tb.inTable = true
+			tb.start, tb.stop = -1, -1
 		}
+		return ast.WalkContinue, nil
+	}
 
-		if tb.inTable {
-			e := tb.NewElement(node, source)
-			e.open(tb.buf)
-		}
-
-	} else {
-		if node.Kind() == astext.KindTable {
-			tb.tables = append(tb.tables, table{tb.rows, tb.cols, tb.alignment})
-			tb.rows = nil
-			tb.cols = nil
-			tb.alignment = nil
-			tb.inTable = false
+	switch node.Kind() {
+	case astext.KindTable:
+		if len(tb.rows) == 0 && tb.start >= 0 {
+			// a table without rows ends with its delimiter row, on the line
+			// after the header
+			if eol := bytes.IndexByte(source[tb.stop:], '\n'); eol >= 0 {
+				tb.stop += eol + 1
+			}
 		}
-
-		if tb.inTable {
-			switch node.Kind() {
-			case astext.KindTableCell:
-				switch node.Parent().Kind() {
-				case astext.KindTableHeader:
-					n := node.(*astext.TableCell)
-					tb.cols = append(tb.cols, tb.buf.String())
-					tb.alignment = append(tb.alignment, n.Alignment.String())
-					tb.buf.Reset()
-				case astext.KindTableRow:
-					tb.rows = append(tb.rows, tb.buf.String())
-					tb.buf.Reset()
-				}
-			default:
-				e := tb.NewElement(node, source)
-				e.close(tb.buf)
+		src, offset := tableSource(source, tb.start, tb.stop)
+		tb.tables = append(tb.tables, table{
+			rows:      tb.rows,
+			cols:      tb.cols,
+			alignment: tb.alignment,
+			source:    src,
+			offset:    offset,
+		})
+		tb.rows = nil
+		tb.cols = nil
+		tb.alignment = nil
+		tb.inTable = false
+	case astext.KindTableCell:
+		if node.Lines().Len() > 0 {
+			seg := node.Lines().At(0)
+			if tb.start < 0 {
+				tb.start = seg.Start
 			}
+			tb.stop = max(seg.Stop, tb.stop)
+		}
+		switch node.Parent().Kind() {
+		case astext.KindTableHeader:
+			n := node.(*astext.TableCell)
+			tb.cols = append(tb.cols, cellSource(node, source))
+			tb.alignment = append(tb.alignment, n.Alignment.String())
+		case astext.KindTableRow:
+			tb.row = append(tb.row, cellSource(node, source))
 		}
+	case astext.KindTableRow:
+		tb.rows = append(tb.rows, append(tb.row, overflowCells(node, source)...))
+		tb.row = nil
 	}
 	return ast.WalkContinue, nil
 }
+
+// cellSource returns the text of a cell as it is in the source, so that the
+// inline markdown of the cell is kept as written.
+func cellSource(node ast.Node, source []byte) string {
+	if node.Lines().Len() == 0 {
+		return ""
+	}
+	seg := node.Lines().At(0)
+	return string(seg.Value(source))
+}
+
+// tableSource returns the lines of the source from the one of start to the
+// one of stop, with the line break of the last one, and the offset of the
+// first line. It returns an empty string and -1 when the table does not
+// start its line, as in a block quote.
+func tableSource(source []byte, start, stop int) (string, int) {
+	if start < 0 {
+		return "", -1
+	}
+	bol := bytes.LastIndexByte(source[:start], '\n') + 1
+	if prefix := trimSpace(string(source[bol:start])); prefix != "" && prefix != "|" {
+		return "", -1
+	}
+	eol := bytes.IndexByte(source[stop:], '\n')
+	if eol < 0 {
+		return string(source[bol:]), bol
+	}
+	return string(source[bol : stop+eol+1]), bol
+}
+
+// overflowCells returns the cells of a row beyond the number of columns of
+// the table, which goldmark drops. They are read from the source line after
+// the last cell of the row.
+func overflowCells(row ast.Node, source []byte) []string {
+	last := row.LastChild()
+	if last == nil || last.Lines().Len() == 0 {
+		return nil
+	}
+
+	stop := last.Lines().At(0).Stop
+	eol := bytes.IndexByte(source[stop:], '\n')
+	if eol < 0 {
+		eol = len(source) - stop
+	}
+	rest := trimSpace(string(source[stop : stop+eol]))
+	if rest == "" || rest == "|" {
+		return nil
+	}
+
+	var cells []string
+	for _, c := range splitCells(rest) {
+		cells = append(cells, trimSpace(c.text))
+	}
+	// empty cells at the end hold no data to keep
+	for len(cells) > 0 && cells[len(cells)-1] == "" {
+		cells = cells[:len(cells)-1]
+	}
+	return cells
+}
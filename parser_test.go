@@ -4,6 +4,8 @@ import (
 	"io"
 	"os"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func TestParse(t *testing.T) {
@@ -63,3 +65,151 @@ func isEqualTables(x, y TableModel) bool {
 	}
 	return ret
 }
+
+func TestParseRaggedRows(t *testing.T) {
+	testCases := []struct {
+		name       string
+		src        string
+		wantTitles []string
+		wantAlign  []string
+		wantRows   [][]string
+	}{
+		{
+			name:       "short row",
+			src:        "| a | b |\n| - | - |\n| 1 |\n| x | y |\n",
+			wantTitles: []string{"a", "b"},
+			wantAlign:  []string{"none", "none"},
+			wantRows:   [][]string{{"1", ""}, {"x", "y"}},
+		},
+		{
+			name:       "overflow row",
+			src:        "| a | b |\n| - | -: |\n| 1 | 2 | 3 |\n| x | y |\n",
+			wantTitles: []string{"a", "b", ""},
+			wantAlign:  []string{"none", "right", "none"},
+			wantRows:   [][]string{{"1", "2", "3"}, {"x", "y", ""}},
+		},
+		{
+			name:       "overflow without pipes",
+			src:        "a | b\n:- | -\n1 | 2 | 3 \\| 4 | 5\n6\n",
+			wantTitles: []string{"a", "b", "", ""},
+			wantAlign:  []string{"left", "none", "none", "none"},
+			wantRows:   [][]string{{"1", "2", "3 \\| 4", "5"}, {"6", "", "", ""}},
+		},
+		{
+			name:       "empty overflow",
+			src:        "| a | b |\n| - | - |\n| 1 | 2 |  |\n",
+			wantTitles: []string{"a", "b"},
+			wantAlign:  []string{"none", "none"},
+			wantRows:   [][]string{{"1", "2"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parse([]byte(tc.src))
+			if len(got) != 1 {
+				t.Fatalf("want 1 table, got %d", len(got))
+			}
+
+			var titles, align []string
+			for _, c := range got[0].cols {
+				titles = append(titles, c.title.value())
+				align = append(align, c.alignment)
+			}
+			if diff := cmp.Diff(tc.wantTitles, titles); diff != "" {
+				t.Errorf("titles differ: (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantAlign, align); diff != "" {
+				t.Errorf("alignments differ: (-want +got)\n%s", diff)
+			}
+			if diff := cmp.Diff(tc.wantRows, values(got[0].rows)); diff != "" {
+				t.Errorf("rows differ: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseWideCharacters(t *testing.T) {
+	testCases := []struct {
+		name       string
+		src        string
+		wantWidths []int
+	}{
+		{
+			name:       "cjk",
+			src:        "| 名前 | id |\n| - | - |\n| 山田太郎 | 1 |\n",
+			wantWidths: []int{10, 4},
+		},
+		{
+			name:       "emoji",
+			src:        "| a | b |\n| - | - |\n| 👨‍👩‍👧 | 🇯🇵 |\n| 👍🏽 | x |\n",
+			wantWidths: []int{4, 4},
+		},
+		{
+			name:       "combining marks",
+			src:        "| a |\n| - |\n| e\u0301te\u0301 |\n",
+			wantWidths: []int{5},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var widths []int
+			for _, c := range parse([]byte(tc.src))[0].cols {
+				widths = append(widths, c.width)
+			}
+			if diff := cmp.Diff(tc.wantWidths, widths); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseEscapes(t *testing.T) {
+	src := "| a \\| b |\n| - |\n| \\*x\\* \\\\ y |\n| `c \\| d` |\n| `\\|` and `e\\|f\\|g` |\n"
+	want := [][]string{
+		{"\\*x\\* \\\\ y"},
+		{"`c \\| d`"},
+		{"`\\|` and `e\\|f\\|g`"},
+	}
+
+	got := parse([]byte(src))
+	if diff := cmp.Diff("a \\| b", got[0].cols[0].title.value()); diff != "" {
+		t.Errorf("title differs: (-want +got)\n%s", diff)
+	}
+	if diff := cmp.Diff(want, values(got[0].rows)); diff != "" {
+		t.Errorf("rows differ: (-want +got)\n%s", diff)
+	}
+}
+
+func TestParseInlineMarkdown(t *testing.T) {
+	cells := []string{
+		"~~x~~",
+		"![alt](img.png \"title\")",
+		"a<br>b",
+		"[t][ref]",
+		"[link](https://example.com \"title\")",
+		"<https://example.com>",
+		"footnote[^1]",
+		":smile:",
+		"*em* __strong__",
+		"`code` \\*not em\\*",
+		"<span style=\"color: red\">red</span>",
+	}
+
+	var src string
+	src += "| a |\n| - |\n"
+	for _, c := range cells {
+		src += "| " + c + " |\n"
+	}
+	src += "\n[ref]: https://example.com\n[^1]: note\n"
+
+	var want [][]string
+	for _, c := range cells {
+		want = append(want, []string{c})
+	}
+	got := parse([]byte(src))
+	if diff := cmp.Diff(want, values(got[0].rows)); diff != "" {
+		t.Errorf("rows differ: (-want +got)\n%s", diff)
+	}
+}
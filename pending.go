@@ -0,0 +1,76 @@
+package mdtt
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxCount bounds count prefixes so that a mistyped count can not hang the UI.
+const maxCount = 9999
+
+// pending is a partly typed command, such as "3d" before the second "d" of
+// "3dd". It stays pending until the next key, however long the user takes to
+// type it.
+type pending struct {
+	count int
+	// seq is the key sequence typed so far, its keys separated by spaces.
+	seq string
+	// keys are the keys typed so far, shown in the status line.
+	keys string
+}
+
+// addDigit adds the digit key to the count. It returns false when msg is not
+// part of a count, "0" being part of one only after another digit.
+func (p *pending) addDigit(msg tea.KeyMsg) bool {
+	if msg.Type != tea.KeyRunes || len(msg.Runes) != 1 || msg.Alt {
+		return false
+	}
+	r := msg.Runes[0]
+	if !isDigit(r) || (r == '0' && p.count == 0) {
+		return false
+	}
+	p.count = min(p.count*10+int(r-'0'), maxCount)
+	p.keys += string(r)
+	return true
+}
+
+// push adds the key of msg to the key sequence. It returns false while the
+// sequence begins a longer one bound in bindings, the command then waiting
+// for its next key.
+func (p *pending) push(msg tea.KeyMsg, bindings []key.Binding) bool {
+	if p.seq != "" {
+		p.seq += " "
+	}
+	p.seq += keyName(msg)
+	p.keys += msg.String()
+	for _, b := range bindings {
+		for _, k := range b.Keys() {
+			if strings.HasPrefix(k, p.seq+" ") {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// is reports whether the key sequence is bound to any of the bindings.
+func (p pending) is(bindings ...key.Binding) bool {
+	for _, b := range bindings {
+		if b.Enabled() && slices.Contains(b.Keys(), p.seq) {
+			return true
+		}
+	}
+	return false
+}
+
+// n returns the count, which defaults to 1.
+func (p pending) n() int {
+	return max(p.count, 1)
+}
+
+func (p *pending) reset() {
+	*p = pending{}
+}
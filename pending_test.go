@@ -0,0 +1,93 @@
+package mdtt
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCount(t *testing.T) {
+	newTable := func() TableModel {
+		var rows []naiveRow
+		for _, v := range []string{"1", "2", "3", "4", "5", "6"} {
+			rows = append(rows, naiveRow{v, v + v, v + v + v, v + v + v + v})
+		}
+		return NewTableModel(
+			WithColumns([]column{
+				{title: NewCell("a"), width: 5},
+				{title: NewCell("b"), width: 5},
+				{title: NewCell("c"), width: 5},
+				{title: NewCell("d"), width: 5},
+			}),
+			WithNaiveRows(rows),
+			WithHeight(6),
+		)
+	}
+
+	testCases := []struct {
+		keys   string
+		rows   int
+		cols   int
+		cursor cursor
+		first  []string
+	}{
+		{keys: "5j", rows: 6, cols: 4, cursor: cursor{y: 5}},
+		{keys: "3l", rows: 6, cols: 4, cursor: cursor{x: 3}},
+		{keys: "9l", rows: 6, cols: 4, cursor: cursor{x: 3}},
+		{keys: "3dd", rows: 3, cols: 4, first: []string{"4"}},
+		{keys: "d3d", rows: 3, cols: 4, first: []string{"4"}},
+		{keys: "2vd", rows: 6, cols: 2, first: []string{"111"}},
+		{keys: "yy10p", rows: 16, cols: 4, cursor: cursor{y: 1}, first: []string{"1"}},
+		{keys: "4o", rows: 10, cols: 4, cursor: cursor{y: 1}, first: []string{"1"}},
+		{keys: "3x", rows: 6, cols: 4, first: []string{"", "", "", "1111"}},
+		{keys: "4G", rows: 6, cols: 4, cursor: cursor{y: 3}},
+		{keys: "2j3k", rows: 6, cols: 4},
+		{keys: "2V2jd", rows: 2, cols: 4, first: []string{"5"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.keys, func(t *testing.T) {
+			m := newTable()
+			for _, k := range keys(tc.keys) {
+				m, _ = m.Update(k)
+			}
+			if len(m.rows) != tc.rows || len(m.cols) != tc.cols {
+				t.Errorf("got %dx%d, want %dx%d", len(m.rows), len(m.cols), tc.rows, tc.cols)
+			}
+			if m.mode == NORMAL && m.cursor != tc.cursor {
+				t.Errorf("got cursor %+v, want %+v", m.cursor, tc.cursor)
+			}
+			for i, want := range tc.first {
+				if got := m.rows[0][i].value(); got != want {
+					t.Errorf("cell %d: got %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPendingOperator(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{{title: NewCell("a"), width: 5}}),
+		WithNaiveRows([]naiveRow{{"1"}, {"2"}}),
+	)
+
+	for _, k := range keys("12d") {
+		m, _ = m.Update(k)
+	}
+	if diff := cmp.Diff(pending{count: 12, seq: "d", keys: "12d"}, m.pending,
+		cmp.AllowUnexported(pending{})); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+	if got := m.statusView(); got != "12d" {
+		t.Errorf("got status %q, want %q", got, "12d")
+	}
+
+	// an unrelated key cancels the operator
+	for _, k := range keys("j") {
+		m, _ = m.Update(k)
+	}
+	if len(m.rows) != 2 || m.pending != (pending{}) {
+		t.Errorf("got %d rows and pending %+v", len(m.rows), m.pending)
+	}
+}
@@ -2,14 +2,14 @@ package mdtt
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"io"
 	"os"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
-
-	"github.com/charmbracelet/log"
 )
 
 type tableWriter struct {
@@ -29,27 +29,102 @@ type tableRange struct {
 	End   int
 }
 
-func Write(m Model) {
-	tw := tableWriter{}
-	tw.render(m.table)
+// Write outputs the modified tables of m. In in-place mode every modified
+// table is replaced in the file, otherwise the tables are printed in the
+// output format of m. When no table is modified, the current table is printed.
+func Write(m Model) error {
 	if m.inplace {
-		if err := tw.writeFile(m); err != nil {
-			log.Error("Error writing file: ", err)
-			return
+		texts := make(map[int]string)
+		for i, t := range m.tables {
+			if t.modified() {
+				text, err := renderFormat(t, FormatMarkdown)
+				if err != nil {
+					return fmt.Errorf("failed to render table %d: %w", i+1, err)
+				}
+				texts[i] = text
+			}
 		}
-	} else {
-		fmt.Print(tw.text)
+		return writeFile(m.fpath, texts)
+	}
+
+	text, err := renderOutput(m)
+	if err != nil {
+		return err
+	}
+	fmt.Print(text)
+	return nil
+}
+
+// renderOutput renders the modified tables of m, or the current table when
+// no table is modified, in the output format of m. Several JSON tables make
+// one array. Several CSV or TSV tables are an error, since they cannot be
+// told apart once printed one after another.
+func renderOutput(m Model) (string, error) {
+	var tables []TableModel
+	var idx []int
+	for i, t := range m.tables {
+		if t.modified() {
+			tables = append(tables, t)
+			idx = append(idx, i)
+		}
+	}
+
+	switch {
+	case len(tables) == 0:
+		text, err := renderFormat(m.table, m.format)
+		if err != nil {
+			return "", fmt.Errorf("failed to render table: %w", err)
+		}
+		return text, nil
+	case len(tables) == 1:
+		text, err := renderFormat(tables[0], m.format)
+		if err != nil {
+			return "", fmt.Errorf("failed to render table %d: %w", idx[0]+1, err)
+		}
+		return text, nil
 	}
+
+	switch m.format {
+	case FormatJSON:
+		text, err := renderJSONTables(tables)
+		if err != nil {
+			return "", fmt.Errorf("failed to render tables: %w", err)
+		}
+		return text, nil
+	case FormatCSV, FormatTSV:
+		return "", fmt.Errorf("cannot print %d modified tables as %s", len(tables), m.format)
+	}
+
+	// markdown and HTML tables are separated by a blank line
+	var out []string
+	for i, t := range tables {
+		text, err := renderFormat(t, m.format)
+		if err != nil {
+			return "", fmt.Errorf("failed to render table %d: %w", idx[i]+1, err)
+		}
+		out = append(out, text)
+	}
+	return strings.Join(out, "\n"), nil
 }
-func (tw *tableWriter) writeFile(m Model) error {
-	fp, err := os.Open(m.fpath)
+
+// writeFile replaces the tables of the file at path with texts, which maps
+// table indices to rendered tables.
+func writeFile(path string, texts map[int]string) error {
+	if len(texts) == 0 {
+		return nil
+	}
+
+	fp, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("failed to open file: %w", err)
 	}
 	defer fp.Close()
-	b := tw.replaceTable(fp, m.choose)
+	b, err := replaceTables(fp, texts)
+	if err != nil {
+		return fmt.Errorf("failed to replace tables: %w", err)
+	}
 
-	if err := os.WriteFile(m.fpath, b, 0644); err != nil {
+	if err := os.WriteFile(path, b, 0644); err != nil {
 		return fmt.Errorf("failed to write file: %w", err)
 	}
 	return nil
@@ -63,37 +138,38 @@ func (t *tableWriter) render(m TableModel) {
 	if runtime.GOOS == "windows" {
 		newline = "\r\n"
 	}
+	widths := outputWidths(m)
 
 	// render header
-	for _, c := range m.cols {
+	for i, c := range m.cols {
 		sb.WriteString("| ")
-		sb.WriteString(padOrTruncate(c.title.value(), max(c.width-1, 2)))
-		width += c.width
+		sb.WriteString(alignText(cellText(c.title.value()), max(widths[i]-1, 2), c.alignment))
+		width += widths[i]
 	}
 	sb.WriteString("|" + newline)
 
 	// render delimiter
-	for _, c := range m.cols {
+	for i, c := range m.cols {
 
 		if c.alignment == "left" {
 			sb.WriteString("|:")
-			sb.WriteString(strings.Repeat("-", max(c.width-2, 1)))
+			sb.WriteString(strings.Repeat("-", max(widths[i]-2, 1)))
 			sb.WriteString(" ")
 			continue
 		} else if c.alignment == "center" {
 			sb.WriteString("|:")
-			sb.WriteString(strings.Repeat("-", max(c.width-2, 1)))
+			sb.WriteString(strings.Repeat("-", max(widths[i]-2, 1)))
 			sb.WriteString(":")
 			continue
 		} else if c.alignment == "right" {
 			sb.WriteString("| ")
-			sb.WriteString(strings.Repeat("-", max(c.width-2, 1)))
+			sb.WriteString(strings.Repeat("-", max(widths[i]-2, 1)))
 			sb.WriteString(":")
 			continue
 		}
 
 		sb.WriteString("| ")
-		sb.WriteString(strings.Repeat("-", max(c.width-2, 1)))
+		sb.WriteString(strings.Repeat("-", max(widths[i]-2, 1)))
 		sb.WriteString(" ")
 	}
 	sb.WriteString("|" + newline)
@@ -102,7 +178,7 @@ func (t *tableWriter) render(m TableModel) {
 	for _, row := range m.rows {
 		for i, c := range row {
 			sb.WriteString("| ")
-			sb.WriteString(padOrTruncate(c.value(), max(m.cols[i].width-1, 2)))
+			sb.WriteString(alignText(cellText(c.value()), max(widths[i]-1, 2), m.cols[i].alignment))
 		}
 		sb.WriteString("|" + newline)
 	}
@@ -110,21 +186,112 @@ func (t *tableWriter) render(m TableModel) {
 	t.text = sb.String()
 }
 
-func (t *tableWriter) replaceTable(fp *os.File, idx int) []byte {
+// outputWidths returns the widths of the columns in the output, which are
+// widened for cells that are longer as markdown than in the table, such as
+// cells with escaped pipes or line breaks.
+func outputWidths(m TableModel) []int {
+	var widths []int
+	for x, c := range m.cols {
+		w := max(c.width, displayWidth(cellText(c.title.value()))+widthPadding)
+		for _, r := range m.rows {
+			w = max(w, displayWidth(cellText(r[x].value()))+widthPadding)
+		}
+		widths = append(widths, w)
+	}
+	return widths
+}
+
+// cellText returns the text of a cell as written in a markdown table: line
+// breaks become <br> and pipes are escaped.
+func cellText(s string) string {
+	return escapePipes(joinLines(s))
+}
+
+// escapePipes escapes the pipes of a cell that are not escaped yet, so that
+// a "|" typed in a cell does not split it.
+func escapePipes(s string) string {
+	if !strings.Contains(s, "|") {
+		return s
+	}
+	var sb strings.Builder
+	for i, r := range s {
+		if r == '|' && (i == 0 || s[i-1] != '\\') {
+			sb.WriteByte('\\')
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+func (t *tableWriter) replaceTable(fp *os.File, idx int) ([]byte, error) {
+	return replaceTables(fp, map[int]string{idx: t.text})
+}
+
+// replaceTables replaces the tables whose indices are keys of texts in one
+// pass. The other tables and the text between them are kept as is. A table
+// that cannot be located, as in a block quote, cannot be replaced.
+func replaceTables(fp io.ReadSeeker, texts map[int]string) ([]byte, error) {
 
 	nlbytesize := 1
 	if runtime.GOOS == "windows" {
 		nlbytesize = 2
 	}
 
+	if _, err := fp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(fp)
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := locateTables(b)
+	for i := range texts {
+		if _, ok := ranges[i]; !ok {
+			return nil, fmt.Errorf("table %d cannot be located", i+1)
+		}
+	}
+
+	idx := make([]int, 0, len(texts))
+	for i := range texts {
+		idx = append(idx, i)
+	}
+	slices.Sort(idx)
+
+	var out []byte
+	var pos int
+	for _, i := range idx {
+		r, text := ranges[i], texts[i]
+		out = append(out, b[pos:r.Start-nlbytesize]...)
+		out = append(out, text...)
+		pos = min(len(b), r.End)
+	}
+	return append(out, b[pos:]...), nil
+}
+
+// locateTables returns the ranges of the tables of b by their indices among
+// the parsed tables. The parser finds tables the locator does not, such as
+// the ones in block quotes, so they are matched by the line they start on.
+func locateTables(b []byte) map[int]tableRange {
+	nlbytesize := 1
+	if runtime.GOOS == "windows" {
+		nlbytesize = 2
+	}
+
 	tl := tableLocator{}
-	tl.findLocations(fp)
+	tl.findLocations(bytes.NewReader(b))
+	starts := make(map[int]tableRange)
+	for _, r := range tl.ranges {
+		starts[r.Start-nlbytesize] = r
+	}
 
-	fp.Seek(0, 0)
-	b, _ := io.ReadAll(fp)
-	b = append(b[:tl.ranges[idx].Start-nlbytesize],
-		append([]byte(t.text), b[min(len(b), tl.ranges[idx].End):]...)...)
-	return b
+	ranges := make(map[int]tableRange)
+	for i, t := range parse(b) {
+		if r, ok := starts[t.offset]; ok && t.source != "" {
+			ranges[i] = r
+		}
+	}
+	return ranges
 }
 
 var (
@@ -4,9 +4,12 @@ import (
 	"bytes"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/google/go-cmp/cmp"
 )
 
@@ -111,10 +114,301 @@ func testUtilReplaceTable(src, wnt string, idx int) ([]byte, []byte) {
 	m.choose = idx
 
 	tw.render(m.table)
-	got := tw.replaceTable(fpSrc, idx)
+	got, _ := tw.replaceTable(fpSrc, idx)
 
 	fpWnt, _ := os.Open(wnt)
 	defer fpWnt.Close()
 	want, _ := io.ReadAll(fpWnt)
 	return got, want
 }
+
+func TestReplaceTables(t *testing.T) {
+	fpWnt, _ := os.Open("testdata/replace07_want.md")
+	defer fpWnt.Close()
+	want, _ := io.ReadAll(fpWnt)
+
+	m, _ := NewUI(WithMarkdown(want))
+	texts := make(map[int]string)
+	for _, idx := range []int{0, 2} {
+		tw := tableWriter{}
+		tw.render(m.tables[idx])
+		texts[idx] = tw.text
+	}
+
+	fpSrc, _ := os.Open("testdata/replace04.md")
+	defer fpSrc.Close()
+	got, err := replaceTables(fpSrc, texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestEscapePipes(t *testing.T) {
+	testCases := []struct {
+		in   string
+		want string
+	}{
+		{"a | b", "a \\| b"},
+		{"a \\| b", "a \\| b"},
+		{"|a|", "\\|a\\|"},
+		{"`x|y`", "`x\\|y`"},
+		{"no pipe", "no pipe"},
+	}
+
+	for _, tc := range testCases {
+		if diff := cmp.Diff(tc.want, escapePipes(tc.in)); diff != "" {
+			t.Errorf("%q differs: (-want +got)\n%s", tc.in, diff)
+		}
+	}
+}
+
+func TestRenderTypedPipe(t *testing.T) {
+	m := parse([]byte("| a |\n| - |\n| b |\n"))[0]
+	for _, k := range keys("xix|y") {
+		m, _ = m.Update(k)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	tw := tableWriter{}
+	tw.render(m)
+	got := parse([]byte(tw.text))[0]
+	if diff := cmp.Diff([][]string{{"x\\|y"}}, values(got.rows)); diff != "" {
+		t.Errorf("rows differ: (-want +got)\n%s", diff)
+	}
+}
+
+func TestRoundTripUnedited(t *testing.T) {
+	src, err := os.ReadFile("testdata/roundtrip.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	texts := make(map[int]string)
+	for i, tbl := range parse(src) {
+		texts[i], err = renderFormat(tbl, FormatMarkdown)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := replaceTables(bytes.NewReader(src), texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(src), string(got)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestRoundTripHeaderOnly(t *testing.T) {
+	src := "# t\n\n| a | b |\n|---|---|\n\nafter\n"
+
+	t.Run("stdout", func(t *testing.T) {
+		for _, src := range []string{src, "| a | b |\n|---|---|"} {
+			m, err := NewUI(WithMarkdown([]byte(src)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := renderOutput(m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(parse([]byte(got))) != 1 {
+				t.Errorf("%q is not a table", got)
+			}
+		}
+	})
+
+	t.Run("inplace", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "header.md")
+		if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		m, err := NewUI(WithMarkdown([]byte(src)), WithFilePath(path), WithInplace(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// the edit is written, then undone and written again
+		var tm tea.Model = m
+		for _, msg := range []tea.Msg{keys("x")[0], writeMsg{}, keys("u")[0], writeMsg{}} {
+			tm, _ = tm.Update(msg)
+		}
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(src, string(got)); diff != "" {
+			t.Errorf("differs: (-want +got)\n%s", diff)
+		}
+	})
+}
+
+func TestRoundTripEdited(t *testing.T) {
+	src, err := os.ReadFile("testdata/roundtrip.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("testdata/roundtrip_want.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tables := parse(src)
+	m := tables[0]
+	for _, k := range keys("jxuxiedited") {
+		m, _ = m.Update(k)
+	}
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	tables[0] = m
+
+	// an edit that is undone leaves the table unedited
+	n := tables[1]
+	for _, k := range keys("xu") {
+		n, _ = n.Update(k)
+	}
+	tables[1] = n
+
+	texts := make(map[int]string)
+	for i, tbl := range tables {
+		texts[i], err = renderFormat(tbl, FormatMarkdown)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	got, err := replaceTables(bytes.NewReader(src), texts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(want), string(got)); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestWriteInplaceMismatch(t *testing.T) {
+	// the table in the block quote is parsed but not located, so it cannot
+	// be replaced
+	src := "> |a|\n> |-|\n> |b|\n\n|c|\n|-|\n|d|\n"
+	path := filepath.Join(t.TempDir(), "quote.md")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := NewUI(WithMarkdown([]byte(src)), WithFilePath(path), WithInplace(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.tables[0], _ = m.tables[0].Update(keys("x")[0])
+
+	if err := Write(m); err == nil {
+		t.Error("want error")
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(src, string(got)); diff != "" {
+		t.Errorf("the file is changed: (-want +got)\n%s", diff)
+	}
+}
+
+func TestRenderOutput(t *testing.T) {
+	src := "| a |\n| - |\n| 1 |\n\n| b |\n| - |\n| 2 |\n\n| c |\n| - |\n| 3 |\n"
+
+	testCases := []struct {
+		format string
+		want   string
+		err    string
+	}{
+		{
+			format: FormatMarkdown,
+			want:   "| a |\n| - |\n|   |\n\n| c |\n| - |\n|   |\n",
+		},
+		{
+			format: FormatJSON,
+			want: `[
+  [
+    {
+      "a": ""
+    }
+  ],
+  [
+    {
+      "c": ""
+    }
+  ]
+]
+`,
+		},
+		{
+			format: FormatHTML,
+			want: "<table>\n  <thead>\n    <tr>\n      <th>a</th>\n    </tr>\n  </thead>\n" +
+				"  <tbody>\n    <tr>\n      <td></td>\n    </tr>\n  </tbody>\n</table>\n\n" +
+				"<table>\n  <thead>\n    <tr>\n      <th>c</th>\n    </tr>\n  </thead>\n" +
+				"  <tbody>\n    <tr>\n      <td></td>\n    </tr>\n  </tbody>\n</table>\n",
+		},
+		{
+			format: FormatCSV,
+			err:    "cannot print 2 modified tables as csv",
+		},
+		{
+			format: FormatTSV,
+			err:    "cannot print 2 modified tables as tsv",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			m, err := NewUI(WithMarkdown([]byte(src)), WithOutputFormat(tc.format))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// the first and the last tables are edited
+			for _, i := range []int{0, 2} {
+				m.tables[i], _ = m.tables[i].Update(keys("x")[0])
+			}
+
+			got, err := renderOutput(m)
+			var gotErr string
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tc.err {
+				t.Fatalf("got error %q, want %q", gotErr, tc.err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderLineBreaks(t *testing.T) {
+	m := parse([]byte("| a |\n| - |\n| b |\n"))[0]
+	m.rows[0][0].setValue("x\ny | z")
+
+	tw := tableWriter{}
+	tw.render(m)
+	want := "| a           |\n| ----------- |\n| x<br>y \\| z |\n"
+	if diff := cmp.Diff(want, strings.ReplaceAll(tw.text, "\r\n", "\n")); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestRenderWideCharacters(t *testing.T) {
+	src := "| 名前 | 絵文字 |\n| :-- | --: |\n| 田中 | 👨‍👩‍👧 |\n| é | 🇯🇵 |\n"
+	m := parse([]byte(src))[0]
+
+	tw := tableWriter{}
+	tw.render(m)
+	want := "| 名前 | 絵文字 |\n" +
+		"|:---- | ------:|\n" +
+		"| 田中 |     👨‍👩‍👧 |\n" +
+		"| é    |     🇯🇵 |\n"
+	if diff := cmp.Diff(want, strings.ReplaceAll(tw.text, "\r\n", "\n")); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
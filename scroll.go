@@ -0,0 +1,172 @@
+package mdtt
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// ellipsis ends the lines of a column that is cut by the edge of the terminal.
+const ellipsis = "…"
+
+// columnView is a column shown in the table, which is narrower than the
+// column when it is cut by the edge of the terminal.
+type columnView struct {
+	x     int
+	width int
+}
+
+// visibleColumns returns the columns that fit in the width of the terminal:
+// the frozen ones, then the ones from m.left on. The last one may be cut.
+// Every column is shown while the width is unknown.
+func (m TableModel) visibleColumns() []columnView {
+	frozen := min(m.frozen, len(m.cols))
+	xs := make([]int, 0, len(m.cols))
+	for x := range frozen {
+		xs = append(xs, x)
+	}
+	for x := max(m.left, frozen); x < len(m.cols); x++ {
+		xs = append(xs, x)
+	}
+
+	pad := m.styles.cell.GetHorizontalFrameSize()
+	room := m.width - frameWidth(tableFrameStyle)
+	cols := make([]columnView, 0, len(xs))
+	for _, x := range xs {
+		w := m.cols[x].width
+		if m.width > 0 && w+pad > room {
+			if room-pad > 0 {
+				cols = append(cols, columnView{x, room - pad})
+			}
+			break
+		}
+		cols = append(cols, columnView{x, w})
+		room -= w + pad
+	}
+	return cols
+}
+
+// columnShown reports whether the column x is shown whole.
+func (m TableModel) columnShown(x int) bool {
+	for _, c := range m.visibleColumns() {
+		if c.x == x {
+			return c.width == m.cols[x].width
+		}
+	}
+	return false
+}
+
+// scrollColumns scrolls the columns after the frozen ones as little as
+// possible to show the cursor column, and back when the terminal has room
+// for the columns scrolled out.
+func (m *TableModel) scrollColumns() {
+	frozen := min(m.frozen, len(m.cols))
+	m.left = clamp(m.left, frozen, max(len(m.cols)-1, frozen))
+	if m.width <= 0 {
+		return
+	}
+
+	if m.cursor.x >= frozen && m.cursor.x < m.left {
+		m.left = m.cursor.x
+	}
+	for m.cursor.x > m.left && !m.columnShown(m.cursor.x) {
+		m.left++
+	}
+	for m.left > frozen && m.columnShown(len(m.cols)-1) {
+		m.left--
+		if !m.columnShown(len(m.cols)-1) || !m.columnShown(m.cursor.x) && m.cursor.x >= frozen {
+			m.left++
+			break
+		}
+	}
+}
+
+// resize fits the table, the command line and the help to a terminal of the
+// given size.
+func (m *TableModel) resize(width, height int) {
+	m.width, m.height = width, height
+	m.help.Width = max(width-frameWidth(tableFrameStyle), 1)
+	// the prompt and the cursor take a column each
+	m.cmdline.Width = max(width-2, 1)
+	m.updateViewport()
+}
+
+// rowsHeight returns the number of lines left for the rows in the height of
+// the terminal by the header, the frame, the status lines and the help.
+func (m TableModel) rowsHeight() int {
+	other := lipgloss.Height(m.headersView()) + 2 +
+		lipgloss.Height(m.statusBarView()) +
+		lipgloss.Height(m.statusView()) +
+		lipgloss.Height(m.help.View(m.keys))
+	return max(m.height-other, 1)
+}
+
+// rowHeight returns the number of lines of the row y, which is taller than
+// one line when a cell has line breaks.
+func (m TableModel) rowHeight(y int) int {
+	h := 1
+	for _, c := range m.rows[y] {
+		h = max(strings.Count(c.view(), "\n")+1, h)
+	}
+	return h
+}
+
+// rowsFit reports whether the rows from y0 to y1 fit in h lines.
+func (m TableModel) rowsFit(y0, y1, h int) bool {
+	for y := y0; y <= y1 && h >= 0; y++ {
+		h -= m.rowHeight(y)
+	}
+	return h >= 0
+}
+
+// scrollRows scrolls the rows as little as possible to show the cursor row,
+// and back when the terminal has room for the rows scrolled out. Every row
+// is shown while the height is unknown.
+func (m *TableModel) scrollRows() {
+	if m.height <= 0 || len(m.rows) == 0 {
+		m.start = 0
+		return
+	}
+
+	m.start = clamp(m.start, 0, len(m.rows)-1)
+	y := clamp(m.cursor.y, 0, len(m.rows)-1)
+	h := m.rowsHeight()
+	if y < m.start {
+		m.start = y
+	}
+	for m.start < y && !m.rowsFit(m.start, y, h) {
+		m.start++
+	}
+	for m.start > 0 && m.rowsFit(m.start-1, len(m.rows)-1, h) {
+		m.start--
+	}
+}
+
+// cmdFreeze freezes the columns up to the cursor, or the first n columns,
+// so that they stay in place while the others scroll. ":freeze 0" unfreezes
+// them.
+func cmdFreeze(m *TableModel, c exCommand) (tea.Cmd, error) {
+	n := m.cursor.x + 1
+	if c.args != "" {
+		var err error
+		n, err = strconv.Atoi(c.args)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid number of columns: %s", c.args)
+		}
+	}
+
+	m.frozen = min(n, len(m.cols))
+	m.updateViewport()
+	switch m.frozen {
+	case 0:
+		m.setMessage("no columns frozen")
+	case 1:
+		m.setMessage("1 column frozen")
+	default:
+		m.setMessage(fmt.Sprintf("%d columns frozen", m.frozen))
+	}
+	return nil, nil
+}
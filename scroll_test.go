@@ -0,0 +1,186 @@
+package mdtt
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScrollColumns(t *testing.T) {
+	// the columns are 4, 10, 25, 8 and 7 wide, and 2 wider with padding
+	src := "| id | name | description | status | owner |\n" +
+		"| - | - | - | - | - |\n" +
+		"| 1 | 山田太郎 | a long description here | open | alice |\n"
+
+	testCases := []struct {
+		name     string
+		width    int
+		freeze   string
+		keys     string
+		wantLeft int
+		want     []columnView
+	}{
+		{
+			name: "unknown width",
+			keys: "llll",
+			want: []columnView{{0, 4}, {1, 10}, {2, 25}, {3, 8}, {4, 7}},
+		},
+		{
+			name:  "last column cut",
+			width: 30,
+			want:  []columnView{{0, 4}, {1, 10}, {2, 8}},
+		},
+		{
+			name:     "scroll to the cursor",
+			width:    30,
+			keys:     "ll",
+			wantLeft: 2,
+			want:     []columnView{{2, 25}},
+		},
+		{
+			name:     "scroll back",
+			width:    30,
+			keys:     "llllhhh",
+			wantLeft: 1,
+			want:     []columnView{{1, 10}, {2, 14}},
+		},
+		{
+			name:     "frozen column",
+			width:    30,
+			freeze:   "freeze 1",
+			keys:     "llll",
+			wantLeft: 3,
+			want:     []columnView{{0, 4}, {3, 8}, {4, 7}},
+		},
+		{
+			name:     "frozen column on the cursor",
+			width:    30,
+			freeze:   "freeze",
+			keys:     "lllh",
+			wantLeft: 2,
+			want:     []columnView{{0, 4}, {2, 20}},
+		},
+		{
+			name:     "column wider than the terminal",
+			width:    20,
+			keys:     "ll",
+			wantLeft: 2,
+			want:     []columnView{{2, 16}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parse([]byte(src))[0]
+			m.switchMode(NORMAL)
+			if tc.width > 0 {
+				m, _ = m.Update(tea.WindowSizeMsg{Width: tc.width, Height: 20})
+			}
+			if tc.freeze != "" {
+				m.executeCommand(tc.freeze)
+			}
+			for _, k := range keys(tc.keys) {
+				m, _ = m.Update(k)
+			}
+
+			if m.left != tc.wantLeft {
+				t.Errorf("want left %d, got %d", tc.wantLeft, m.left)
+			}
+			if diff := cmp.Diff(tc.want, m.visibleColumns(), cmp.AllowUnexported(columnView{})); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestScrollView(t *testing.T) {
+	src := "| id | description |\n| - | - |\n| 1 | a long description here |\n"
+	m := parse([]byte(src))[0]
+	m.switchMode(NORMAL)
+	m, _ = m.Update(tea.WindowSizeMsg{Width: 20, Height: 20})
+
+	frame := frameView(m.View())
+	want := "┌──────────────────┐\n" +
+		"│ id    descripti… │\n" +
+		"│──────────────────│\n" +
+		"│ 1     a long de… │\n" +
+		"└──────────────────┘"
+	if diff := cmp.Diff(want, frame); diff != "" {
+		t.Errorf("differs: (-want +got)\n%s", diff)
+	}
+}
+
+func TestScrollRows(t *testing.T) {
+	var sb strings.Builder
+	sb.WriteString("| n |\n| - |\n")
+	for i := range 20 {
+		if i == 10 {
+			sb.WriteString("| a<br>b<br>c |\n")
+			continue
+		}
+		fmt.Fprintf(&sb, "| %d |\n", i)
+	}
+
+	// 5 lines are left for the rows in a terminal of 12 lines
+	testCases := []struct {
+		name      string
+		height    int
+		keys      string
+		wantStart int
+		wantEnd   int
+	}{
+		{name: "unknown height", keys: "jjjjjj", wantStart: 0, wantEnd: 20},
+		{name: "top", height: 12, keys: "jjjj", wantStart: 0, wantEnd: 5},
+		{name: "scroll down", height: 12, keys: "jjjjjj", wantStart: 2, wantEnd: 7},
+		{name: "scroll up", height: 12, keys: "jjjjjjkkkkk", wantStart: 1, wantEnd: 6},
+		{name: "taller row", height: 12, keys: "9jjj", wantStart: 9, wantEnd: 12},
+		{name: "bottom", height: 12, keys: "G", wantStart: 15, wantEnd: 20},
+		{name: "terminal taller than the table", height: 40, keys: "G", wantStart: 0, wantEnd: 20},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := parse([]byte(sb.String()))[0]
+			m.switchMode(NORMAL)
+			if tc.height > 0 {
+				m, _ = m.Update(tea.WindowSizeMsg{Width: 40, Height: tc.height})
+			}
+			for _, k := range keys(tc.keys) {
+				m, _ = m.Update(k)
+			}
+
+			if m.start != tc.wantStart || m.end != tc.wantEnd {
+				t.Errorf("want rows %d to %d, got %d to %d", tc.wantStart, tc.wantEnd, m.start, m.end)
+			}
+			if lines := strings.Count(m.View(), "\n") + 1; tc.height > 0 && lines > tc.height {
+				t.Errorf("the view is %d lines tall, the terminal %d", lines, tc.height)
+			}
+		})
+	}
+}
+
+func TestResize(t *testing.T) {
+	src := "| a |\n| - |\n| 1 |\n\n| b |\n| - |\n| 2 |\n"
+	m, err := NewUI(WithMarkdown([]byte(src)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tm, _ := m.Update(tea.WindowSizeMsg{Width: 50, Height: 20})
+	tm, _ = tm.Update(selectMsg{idx: 1})
+	m = tm.(Model)
+	for i, table := range append(m.tables, m.table) {
+		// the line below the table is kept for the quit prompt
+		if table.width != 50 || table.height != 19 {
+			t.Errorf("table %d: want size 50x19, got %dx%d", i, table.width, table.height)
+		}
+	}
+
+	tm, _ = tm.Update(pickerMsg{})
+	if got := tm.(Model).list.list.Width(); got != 50 {
+		t.Errorf("want the list to be 50 wide, got %d", got)
+	}
+}
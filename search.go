@@ -0,0 +1,235 @@
+package mdtt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// search holds the state of the last search.
+type search struct {
+	pattern  string
+	backward bool
+	re       *regexp.Regexp
+	// highlight is set while the matches of re are highlighted.
+	highlight bool
+	// origin is the position the incremental search started from.
+	origin position
+}
+
+// position is a cell position. The header cells are on row -1.
+type position struct {
+	x, y int
+}
+
+// searchOptions are set with the :set command.
+type searchOptions struct {
+	ignoreCase bool
+	regex      bool
+}
+
+// compileSearch compiles a search pattern. The pattern is literal unless
+// the regex option is set. "\c" and "\C" anywhere in the pattern force
+// case-insensitive and case-sensitive matching.
+func compileSearch(pattern string, opts searchOptions) (*regexp.Regexp, error) {
+	ignoreCase := opts.ignoreCase
+	if strings.Contains(pattern, `\c`) {
+		ignoreCase = true
+		pattern = strings.ReplaceAll(pattern, `\c`, "")
+	}
+	if strings.Contains(pattern, `\C`) {
+		ignoreCase = false
+		pattern = strings.ReplaceAll(pattern, `\C`, "")
+	}
+
+	if !opts.regex {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if ignoreCase {
+		pattern = "(?i)" + pattern
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("E486: Invalid pattern: %s", err)
+	}
+	return re, nil
+}
+
+func (m *TableModel) openSearch(backward bool) {
+	m.search.origin = m.position()
+	m.openCommandLine()
+	m.mode = SEARCH
+	m.cmdline.Prompt = m.cmdlinePrompt(backward)
+}
+
+// updateSearch moves the cursor to the first match of the pattern being
+// typed, starting from where the search began.
+func (m *TableModel) updateSearch() {
+	// move in the mode the search was started from
+	m.mode = m.cmdlineMode
+	defer func() {
+		m.cmdlineMode = m.mode
+		m.mode = SEARCH
+		m.updateViewport()
+	}()
+
+	m.gotoPosition(m.search.origin)
+	m.search.highlight = false
+
+	pattern := m.cmdline.Value()
+	if pattern == "" {
+		return
+	}
+	re, err := compileSearch(pattern, m.searchOptions)
+	if err != nil {
+		return
+	}
+	m.search.re = re
+	m.search.highlight = true
+	backward := m.cmdline.Prompt == "?"
+	if p, ok := m.findMatch(m.search.origin, backward); ok {
+		m.gotoPosition(p)
+	}
+}
+
+// confirmSearch ends the search started with openSearch.
+func (m *TableModel) confirmSearch(pattern string, backward bool) {
+	m.gotoPosition(m.search.origin)
+	m.search.backward = backward
+	if pattern == "" {
+		pattern = m.search.pattern
+	}
+	if pattern == "" {
+		m.search.highlight = false
+		m.updateViewport()
+		return
+	}
+
+	re, err := compileSearch(pattern, m.searchOptions)
+	if err != nil {
+		m.setError(err)
+		return
+	}
+	m.search.pattern = pattern
+	m.search.re = re
+	m.search.highlight = true
+	m.searchNext(false)
+}
+
+// cancelSearch ends the search, restoring the cursor and the last search.
+func (m *TableModel) cancelSearch() {
+	m.gotoPosition(m.search.origin)
+	m.search.re = nil
+	m.search.highlight = false
+	if m.search.pattern != "" {
+		m.search.re, _ = compileSearch(m.search.pattern, m.searchOptions)
+	}
+	m.updateViewport()
+}
+
+// searchNext jumps to the next match of the last search. reverse searches in
+// the opposite direction of the last search.
+func (m *TableModel) searchNext(reverse bool) {
+	if m.search.re == nil {
+		m.setError(fmt.Errorf("E35: No previous regular expression"))
+		return
+	}
+	backward := m.search.backward != reverse
+	m.search.highlight = true
+
+	from := m.position()
+	p, ok := m.findMatch(from, backward)
+	if !ok {
+		m.setError(fmt.Errorf("E486: Pattern not found: %s", m.search.pattern))
+		m.updateViewport()
+		return
+	}
+
+	m.setMessage(m.cmdlinePrompt(backward) + m.search.pattern)
+	if backward && m.index(p) >= m.index(from) {
+		m.setMessage("search hit TOP, continuing at BOTTOM")
+	} else if !backward && m.index(p) <= m.index(from) {
+		m.setMessage("search hit BOTTOM, continuing at TOP")
+	}
+	m.gotoPosition(p)
+}
+
+func (m TableModel) cmdlinePrompt(backward bool) string {
+	if backward {
+		return "?"
+	}
+	return "/"
+}
+
+// findMatch returns the first cell after from that matches the search,
+// wrapping around the table. The header cells come before the body cells.
+func (m TableModel) findMatch(from position, backward bool) (position, bool) {
+	n := (len(m.rows) + 1) * len(m.cols)
+	if n == 0 || m.search.re == nil {
+		return position{}, false
+	}
+
+	dir := 1
+	if backward {
+		dir = -1
+	}
+	start := m.index(from)
+	for i := 1; i <= n; i++ {
+		p := m.positionAt(((start+i*dir)%n + n) % n)
+		if m.search.re.MatchString(m.valueAt(p)) {
+			return p, true
+		}
+	}
+	return position{}, false
+}
+
+// position returns the position of the cursor.
+func (m TableModel) position() position {
+	if m.mode == HEADER || m.mode == HEADER_INSERT || len(m.rows) == 0 {
+		return position{x: m.cursor.x, y: -1}
+	}
+	return position{x: m.cursor.x, y: m.cursor.y}
+}
+
+func (m TableModel) index(p position) int {
+	return (p.y+1)*len(m.cols) + p.x
+}
+
+func (m TableModel) positionAt(i int) position {
+	return position{x: i % len(m.cols), y: i/len(m.cols) - 1}
+}
+
+func (m TableModel) valueAt(p position) string {
+	if p.y < 0 {
+		return m.cols[p.x].title.value()
+	}
+	return m.rows[p.y][p.x].value()
+}
+
+// gotoPosition moves the cursor to p.
+func (m *TableModel) gotoPosition(p position) {
+	m.cursor.x = clamp(p.x, 0, len(m.cols)-1)
+	m.gotoRow(p.y + 1)
+	m.updateViewport()
+}
+
+// highlightMatches highlights the parts of s matched by the last search.
+func (m TableModel) highlightMatches(s string) string {
+	if !m.search.highlight || m.search.re == nil {
+		return s
+	}
+
+	var sb strings.Builder
+	var pos int
+	for _, loc := range m.search.re.FindAllStringIndex(s, -1) {
+		if loc[0] == loc[1] {
+			continue
+		}
+		sb.WriteString(s[pos:loc[0]])
+		sb.WriteString(searchMatchStyle.Render(s[loc[0]:loc[1]]))
+		pos = loc[1]
+	}
+	sb.WriteString(s[pos:])
+	return sb.String()
+}
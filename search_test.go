@@ -0,0 +1,84 @@
+package mdtt
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestCompileSearch(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern string
+		opts    searchOptions
+		s       string
+		want    bool
+	}{
+		{name: "literal", pattern: "a.c", s: "abc", want: false},
+		{name: "regex", pattern: "a.c", opts: searchOptions{regex: true}, s: "abc", want: true},
+		{name: "case sensitive", pattern: "ABC", s: "abc", want: false},
+		{name: "ignore case", pattern: "ABC", opts: searchOptions{ignoreCase: true}, s: "abc", want: true},
+		{name: `\c`, pattern: `ABC\c`, s: "abc", want: true},
+		{name: `\C`, pattern: `ABC\C`, opts: searchOptions{ignoreCase: true}, s: "abc", want: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			re, err := compileSearch(tc.pattern, tc.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := re.MatchString(tc.s); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{
+			{title: NewCell("foo"), width: 5},
+			{title: NewCell("bar"), width: 5},
+		}),
+		WithNaiveRows([]naiveRow{
+			{"a", "foo"},
+			{"b", "c"},
+			{"foo", "d"},
+		}),
+	)
+
+	for _, r := range "/foo" {
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	if m.mode != SEARCH || m.cursor != (cursor{x: 1, y: 0}) {
+		t.Fatalf("incremental search: got mode %d cursor %+v", m.mode, m.cursor)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.mode != NORMAL || m.cursor != (cursor{x: 1, y: 0}) {
+		t.Fatalf("search: got mode %d cursor %+v", m.mode, m.cursor)
+	}
+
+	m.searchNext(false)
+	if m.cursor != (cursor{x: 0, y: 2}) {
+		t.Errorf("next: got cursor %+v", m.cursor)
+	}
+
+	m.searchNext(false)
+	if m.mode != HEADER || m.cursor.x != 0 {
+		t.Errorf("next with wrap: got mode %d cursor %+v", m.mode, m.cursor)
+	}
+
+	m.searchNext(true)
+	if m.mode != NORMAL || m.cursor != (cursor{x: 0, y: 2}) {
+		t.Errorf("previous with wrap: got mode %d cursor %+v", m.mode, m.cursor)
+	}
+
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("#")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	m, _ = m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.mode != NORMAL || m.cursor != (cursor{x: 0, y: 2}) || m.search.pattern != "foo" {
+		t.Errorf("cancel: got mode %d cursor %+v pattern %q", m.mode, m.cursor, m.search.pattern)
+	}
+}
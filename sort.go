@@ -0,0 +1,319 @@
+package mdtt
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Enum of sort kinds
+const (
+	sortText = iota
+	sortIgnoreCase
+	sortNumeric
+	sortVersion
+	sortDate
+)
+
+var sortKinds = map[string]int{
+	"t": sortText,
+	"i": sortIgnoreCase,
+	"n": sortNumeric,
+	"v": sortVersion,
+	"d": sortDate,
+}
+
+// sortKey is one key of a multi-key sort.
+type sortKey struct {
+	col  int
+	kind int
+	desc bool
+}
+
+// dateLayouts are the date formats recognized by the date sort.
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02",
+	"2006.01.02",
+	"2006-01",
+	"01/02/2006",
+	"02 Jan 2006",
+	"2 Jan 2006",
+	"02 January 2006",
+	"2 January 2006",
+	"Jan 2, 2006",
+	"January 2, 2006",
+	"Jan 2006",
+	"January 2006",
+}
+
+func cmdSort(m *TableModel, c exCommand) (tea.Cmd, error) {
+	keys, err := m.parseSortKeys(c.args, c.bang)
+	if err != nil {
+		return nil, err
+	}
+	if len(m.rows) == 0 {
+		return nil, nil
+	}
+
+	m.saveSnapshot()
+	m.sortRows(keys)
+	m.dropSnapshotIfUnchanged()
+	m.updateViewport()
+	return nil, nil
+}
+
+// parseSortKeys parses the arguments of :sort. With no argument or a single
+// kind ("n", "v", "d", "i" or "t") the current column is the only key.
+// Otherwise every argument is a key of the form column[:kind][!], where column
+// is a header title or a 1-based index and "!" sorts in descending order.
+// bang reverses the order of every key.
+func (m TableModel) parseSortKeys(args string, bang bool) ([]sortKey, error) {
+	fields := strings.Fields(args)
+	if len(fields) == 0 {
+		return []sortKey{{col: m.cursor.x, kind: sortText, desc: bang}}, nil
+	}
+	if kind, ok := sortKinds[fields[0]]; ok && len(fields) == 1 {
+		return []sortKey{{col: m.cursor.x, kind: kind, desc: bang}}, nil
+	}
+
+	var keys []sortKey
+	for _, f := range fields {
+		k := sortKey{kind: sortText, desc: bang}
+		if strings.HasSuffix(f, "!") {
+			k.desc = !k.desc
+			f = strings.TrimSuffix(f, "!")
+		}
+		if name, kind, ok := strings.Cut(f, ":"); ok {
+			var found bool
+			if k.kind, found = sortKinds[kind]; !found {
+				return nil, fmt.Errorf("E474: Invalid sort kind: %s", kind)
+			}
+			f = name
+		}
+
+		col, err := m.findColumn(f)
+		if err != nil {
+			return nil, err
+		}
+		k.col = col
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+// findColumn returns the index of the column referred to by s, which is a
+// header title or a 1-based index.
+func (m TableModel) findColumn(s string) (int, error) {
+	for i, c := range m.cols {
+		if c.title.value() == s {
+			return i, nil
+		}
+	}
+	for i, c := range m.cols {
+		if strings.EqualFold(c.title.value(), s) {
+			return i, nil
+		}
+	}
+	if i, err := strconv.Atoi(s); err == nil && i >= 1 && i <= len(m.cols) {
+		return i - 1, nil
+	}
+	return 0, fmt.Errorf("E474: No such column: %s", s)
+}
+
+// sortRows sorts the rows by keys. The sort is stable, so rows that compare
+// equal keep their order.
+func (m *TableModel) sortRows(keys []sortKey) {
+	sort.SliceStable(m.rows, func(i, j int) bool {
+		for _, k := range keys {
+			if c := compareSortValues(m.rows[i][k.col].value(), m.rows[j][k.col].value(), k); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// compareSortValues compares a and b by the kind of k. Values that cannot be
+// parsed as the kind always sort after the others.
+func compareSortValues(a, b string, k sortKey) int {
+	var c int
+	switch k.kind {
+	case sortIgnoreCase:
+		c = strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	case sortNumeric:
+		fa, okA := parseNumber(a)
+		fb, okB := parseNumber(b)
+		if okA != okB {
+			return compareFirst(okA)
+		}
+		if okA {
+			c = cmp.Compare(fa, fb)
+		} else {
+			c = compareNatural(a, b)
+		}
+	case sortVersion:
+		va, okA := parseVersion(a)
+		vb, okB := parseVersion(b)
+		if okA != okB {
+			return compareFirst(okA)
+		}
+		if okA {
+			c = compareVersions(va, vb)
+		} else {
+			c = compareNatural(a, b)
+		}
+	case sortDate:
+		ta, okA := parseDate(a)
+		tb, okB := parseDate(b)
+		if okA != okB {
+			return compareFirst(okA)
+		}
+		if okA {
+			c = ta.Compare(tb)
+		} else {
+			c = compareNatural(a, b)
+		}
+	default:
+		c = strings.Compare(a, b)
+	}
+
+	if k.desc {
+		return -c
+	}
+	return c
+}
+
+// compareFirst returns -1 when a sorts first and 1 otherwise.
+func compareFirst(aFirst bool) int {
+	if aFirst {
+		return -1
+	}
+	return 1
+}
+
+// compareNatural compares numbers by value, and other strings chunk by chunk
+// with runs of digits compared by value, so that "item2" < "item10".
+func compareNatural(a, b string) int {
+	fa, okA := parseNumber(a)
+	fb, okB := parseNumber(b)
+	if okA && okB {
+		return cmp.Compare(fa, fb)
+	}
+
+	for a != "" && b != "" {
+		ca, restA := nextChunk(a)
+		cb, restB := nextChunk(b)
+		if c := compareChunks(ca, cb); c != 0 {
+			return c
+		}
+		a, b = restA, restB
+	}
+	return strings.Compare(a, b)
+}
+
+// parseNumber parses s as a number with optional thousands separators, such
+// as "-1.5" or "1,000".
+func parseNumber(s string) (float64, bool) {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", ""), 64)
+	return f, err == nil
+}
+
+// nextChunk splits off the leading run of digits or non-digits of s.
+func nextChunk(s string) (string, string) {
+	digit := isDigit(rune(s[0]))
+	i := strings.IndexFunc(s, func(r rune) bool { return isDigit(r) != digit })
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], s[i:]
+}
+
+func compareChunks(a, b string) int {
+	if isDigit(rune(a[0])) && isDigit(rune(b[0])) {
+		a, b = strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+		if len(a) != len(b) {
+			return compareFirst(len(a) < len(b))
+		}
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+// version is a parsed semantic version.
+type version struct {
+	core       []int
+	prerelease []string
+}
+
+// parseVersion parses versions such as "1.2", "v1.2.3" and "1.2.3-rc.1+build".
+func parseVersion(s string) (version, bool) {
+	s = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(s), "v"), "V")
+	s, _, _ = strings.Cut(s, "+")
+	s, pre, hasPre := strings.Cut(s, "-")
+
+	var v version
+	for _, p := range strings.Split(s, ".") {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return version{}, false
+		}
+		v.core = append(v.core, n)
+	}
+	if hasPre {
+		v.prerelease = strings.Split(pre, ".")
+	}
+	return v, true
+}
+
+func compareVersions(a, b version) int {
+	for i := 0; i < max(len(a.core), len(b.core)); i++ {
+		var x, y int
+		if i < len(a.core) {
+			x = a.core[i]
+		}
+		if i < len(b.core) {
+			y = b.core[i]
+		}
+		if x != y {
+			return compareFirst(x < y)
+		}
+	}
+
+	// a version without pre-release identifiers is the greater one
+	switch {
+	case a.prerelease == nil && b.prerelease == nil:
+		return 0
+	case a.prerelease == nil:
+		return 1
+	case b.prerelease == nil:
+		return -1
+	}
+	for i := 0; i < min(len(a.prerelease), len(b.prerelease)); i++ {
+		if c := compareNatural(a.prerelease[i], b.prerelease[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a.prerelease) - len(b.prerelease)
+}
+
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,120 @@
+package mdtt
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompareSortValues(t *testing.T) {
+	testCases := []struct {
+		name string
+		kind int
+		a, b string
+		want int
+	}{
+		{name: "text", kind: sortText, a: "B", b: "a", want: -1},
+		{name: "ignore case", kind: sortIgnoreCase, a: "B", b: "a", want: 1},
+		{name: "numeric", kind: sortNumeric, a: "9", b: "10", want: -1},
+		{name: "negative", kind: sortNumeric, a: "-1.5", b: "1,000", want: -1},
+		{name: "natural", kind: sortNumeric, a: "item2", b: "item10", want: -1},
+		{name: "not a number", kind: sortNumeric, a: "n/a", b: "10", want: 1},
+		{name: "empty number", kind: sortNumeric, a: "", b: "-5", want: 1},
+		{name: "version", kind: sortVersion, a: "v1.10.0", b: "1.9.3", want: 1},
+		{name: "pre-release", kind: sortVersion, a: "1.0.0-rc.2", b: "1.0.0", want: -1},
+		{name: "pre-release identifiers", kind: sortVersion, a: "1.0.0-rc.2", b: "1.0.0-rc.10", want: -1},
+		{name: "invalid version", kind: sortVersion, a: "unreleased", b: "0.1", want: 1},
+		{name: "date", kind: sortDate, a: "2024-03-01", b: "Feb 1, 2024", want: 1},
+		{name: "invalid date", kind: sortDate, a: "TBD", b: "2024-01-01", want: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := compareSortValues(tc.a, tc.b, sortKey{kind: tc.kind})
+			if got != tc.want {
+				t.Errorf("got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSortRows(t *testing.T) {
+	newTable := func() TableModel {
+		return NewTableModel(
+			WithColumns([]column{
+				{title: NewCell("Name"), width: 5},
+				{title: NewCell("Version"), width: 5},
+			}),
+			WithNaiveRows([]naiveRow{
+				{"b", "1.10"},
+				{"a", "1.2"},
+				{"b", "1.2"},
+				{"a", "2.0"},
+			}),
+		)
+	}
+	values := func(m TableModel) []string {
+		var got []string
+		for _, r := range m.rows {
+			got = append(got, r[0].value()+" "+r[1].value())
+		}
+		return got
+	}
+
+	testCases := []struct {
+		cmd  string
+		want []string
+	}{
+		{cmd: "sort", want: []string{"a 1.2", "a 2.0", "b 1.10", "b 1.2"}},
+		{cmd: "sort!", want: []string{"b 1.10", "b 1.2", "a 1.2", "a 2.0"}},
+		{cmd: "sort Version:v", want: []string{"a 1.2", "b 1.2", "b 1.10", "a 2.0"}},
+		{cmd: "sort Name Version:v!", want: []string{"a 2.0", "a 1.2", "b 1.10", "b 1.2"}},
+		{cmd: "sort 2:v name", want: []string{"a 1.2", "b 1.2", "b 1.10", "a 2.0"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cmd, func(t *testing.T) {
+			m := newTable()
+			m.executeCommand(tc.cmd)
+			if m.messageErr {
+				t.Fatal(m.message)
+			}
+			if diff := cmp.Diff(tc.want, values(m)); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+
+	m := newTable()
+	m.executeCommand("sort Foo")
+	if !m.messageErr {
+		t.Error("expected an error for an unknown column")
+	}
+}
+
+func TestSortNumericMixed(t *testing.T) {
+	testCases := []struct {
+		cmd  string
+		want [][]string
+	}{
+		{cmd: "sort 1:n", want: [][]string{{"-1"}, {"9"}, {"1,000"}, {""}, {"n/a"}}},
+		{cmd: "sort 1:n!", want: [][]string{{"1,000"}, {"9"}, {"-1"}, {"n/a"}, {""}}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.cmd, func(t *testing.T) {
+			m := NewTableModel(
+				WithColumns([]column{{title: NewCell("Qty"), width: 5}}),
+				WithNaiveRows([]naiveRow{{"1,000"}, {"n/a"}, {"9"}, {""}, {"-1"}}),
+			)
+			m.executeCommand(tc.cmd)
+			if m.messageErr {
+				t.Fatal(m.message)
+			}
+			// the cells that are not numbers sort last both ways
+			if diff := cmp.Diff(tc.want, values(m.rows)); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package mdtt
+
+import (
+	"fmt"
+	"strings"
+)
+
+// modeNames are the names of the modes shown in the status bar.
+var modeNames = map[int]string{
+	NORMAL:        "NORMAL",
+	INSERT:        "INSERT",
+	HEADER:        "HEADER",
+	HEADER_INSERT: "INSERT",
+	HELP:          "HELP",
+	COMMAND:       "COMMAND",
+	SEARCH:        "SEARCH",
+	SUBSTITUTE:    "SUBSTITUTE",
+	VISUAL:        "VISUAL BLOCK",
+	VISUAL_LINE:   "VISUAL LINE",
+	VISUAL_COLUMN: "VISUAL COLUMN",
+}
+
+// setLocation tells the table where it comes from for the status bar: it is
+// the table index, counted from 0, of the count tables of the file at path.
+func (m *TableModel) setLocation(path string, index, count int) {
+	m.path = path
+	m.tableIndex = index
+	m.tableCount = count
+}
+
+// statusBarView renders the line below the table frame with the mode, the
+// file, whether the table is modified, the current column and the position
+// of the cursor. The file is cut to fit in the width of the terminal.
+func (m TableModel) statusBarView() string {
+	mode := statusModeStyle.Render(" " + modeNames[m.mode] + " ")
+
+	path := m.path
+	if path == "" {
+		path = "[No Name]"
+	}
+	left := " " + path
+	if m.modified() {
+		left += " [+]"
+	}
+	if m.tableCount > 1 {
+		left += fmt.Sprintf("  table %d/%d", m.tableIndex+1, m.tableCount)
+	}
+
+	var right string
+	if len(m.cols) > 0 {
+		row := m.cursor.y + 1
+		if m.baseMode() == HEADER || m.baseMode() == HEADER_INSERT {
+			row = 0
+		}
+		right = fmt.Sprintf("row %d/%d  col %d/%d ", row, len(m.rows), m.cursor.x+1, len(m.cols))
+		// the title of the column goes first when the terminal is narrow
+		title := strings.Join(cellLines(m.cols[m.cursor.x].title.value()), " ")
+		if title != "" && (m.width <= 0 || displayWidth(mode+title+right)+2 <= m.width) {
+			right = title + "  " + right
+		}
+	}
+
+	if m.width <= 0 {
+		return mode + statusBarStyle.Render(left+"  "+right)
+	}
+	room := m.width - displayWidth(mode) - displayWidth(right)
+	left = alignLine(truncate(left, max(room-1, 0), ellipsis), room, "left")
+	return truncate(mode+statusBarStyle.Render(left+right), m.width, ellipsis)
+}
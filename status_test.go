@@ -0,0 +1,103 @@
+package mdtt
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestStatusBar(t *testing.T) {
+	src := "| Name | Status |\n| - | - |\n| a | b |\n| c | d |\n\n| x |\n| - |\n| 1 |\n"
+
+	testCases := []struct {
+		name  string
+		path  string
+		width int
+		keys  string
+		want  string
+	}{
+		{
+			name: "normal",
+			path: "tables.md",
+			keys: "j",
+			want: " NORMAL  tables.md  table 1/2  Name  row 2/2  col 1/2 ",
+		},
+		{
+			name: "header",
+			path: "tables.md",
+			keys: "kl",
+			want: " HEADER  tables.md  table 1/2  Status  row 0/2  col 2/2 ",
+		},
+		{
+			name: "modified",
+			path: "tables.md",
+			keys: "x",
+			want: " NORMAL  tables.md [+]  table 1/2  Name  row 1/2  col 1/2 ",
+		},
+		{
+			name: "visual",
+			keys: "V",
+			want: " VISUAL LINE  [No Name]  table 1/2  Name  row 1/2  col 1/2 ",
+		},
+		{
+			name:  "narrow",
+			path:  "docs/tables.md",
+			width: 40,
+			want:  " NORMAL  docs/t… Name  row 1/2  col 1/2 ",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m, err := NewUI(WithMarkdown([]byte(src)), WithFilePath(tc.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var tm tea.Model = m
+			if tc.width > 0 {
+				tm, _ = tm.Update(tea.WindowSizeMsg{Width: tc.width, Height: 20})
+			}
+			tm, _ = tm.Update(selectMsg{idx: 0})
+			for _, k := range keys(tc.keys) {
+				tm, _ = tm.Update(k)
+			}
+
+			if got := tm.(Model).table.statusBarView(); got != tc.want {
+				t.Errorf("want %q, got %q", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestWriteError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing", "table.md")
+	m, err := NewUI(
+		WithMarkdown([]byte("| a |\n| - |\n| 1 |\n")),
+		WithFilePath(path),
+		WithInplace(true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tm tea.Model = m
+	for _, k := range keys("x") {
+		tm, _ = tm.Update(k)
+	}
+	tm, _ = tm.Update(writeMsg{})
+	m = tm.(Model)
+
+	if !m.table.messageErr || !strings.Contains(m.table.message, "failed to open file") {
+		t.Errorf("want a write error in the status line, got %q", m.table.message)
+	}
+	if !m.modified() {
+		t.Error("want the table to stay modified")
+	}
+
+	_, cmd := tm.Update(writeQuitMsg{})
+	if cmd != nil {
+		t.Error("want to stay in the editor when writing fails")
+	}
+}
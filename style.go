@@ -6,46 +6,115 @@ import (
 )
 
 var (
-	tableFrameStyle = lipgloss.NewStyle().
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("240"))
-	// cell styles
-	cellCursorStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("#825DF2"))
-
 	// list styles
 	listItemStyle = lipgloss.NewStyle().
 			PaddingLeft(2)
 
-	listSelectedItemStyle = lipgloss.NewStyle().
-				PaddingLeft(0).
-				Foreground(lipgloss.Color("#825DF2"))
-
 	listPaginationStyle = list.DefaultStyles().
 				PaginationStyle.PaddingLeft(4)
 
 	listHelpStyle = list.DefaultStyles().
 			HelpStyle.PaddingLeft(4).PaddingBottom(1)
 
+	// table styles
+	tableCellStyle = lipgloss.NewStyle().
+			Padding(0, 1)
+
+	statusMessageStyle = lipgloss.NewStyle()
+)
+
+// The styles with colors, set by SetTheme.
+var (
+	tableFrameStyle       lipgloss.Style
+	cellCursorStyle       lipgloss.Style
+	listSelectedItemStyle lipgloss.Style
+	listHeaderStyle       lipgloss.Style
+	tableSelectedStyle    lipgloss.Style
+	tableHeaderStyle      lipgloss.Style
+	tableVisualStyle      lipgloss.Style
+	searchMatchStyle      lipgloss.Style
+	statusBarStyle        lipgloss.Style
+	statusModeStyle       lipgloss.Style
+	statusErrorStyle      lipgloss.Style
+	promptStyle           lipgloss.Style
+)
+
+func init() {
+	SetTheme(themes[ThemeDark])
+}
+
+// SetTheme sets the colors of the UI, before it is created. The elements a
+// theme gives no colors to are reversed, underlined or bold instead.
+func SetTheme(t Theme) {
+	tableFrameStyle = lipgloss.NewStyle().
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(color(t.Border))
+
+	// cell styles
+	cellCursorStyle = lipgloss.NewStyle().
+		Foreground(color(t.Accent))
+
+	// list styles
+	listSelectedItemStyle = lipgloss.NewStyle().
+		PaddingLeft(0).
+		Foreground(color(t.Accent)).
+		Bold(t.Accent == "")
+
 	listHeaderStyle = lipgloss.NewStyle().Bold(true).Padding(0, 0).
-			Border(lipgloss.NormalBorder(), false, false, true, false).
-			BorderForeground(lipgloss.Color("240")).
-			Foreground(lipgloss.Color("249"))
+		Border(lipgloss.NormalBorder(), false, false, true, false).
+		BorderForeground(color(t.Border)).
+		Foreground(color(t.Title))
 
 	// table styles
 	tableSelectedStyle = lipgloss.NewStyle().
-				Bold(true).
-				Foreground(lipgloss.Color("#FFFFFF")).
-				Background(lipgloss.Color("#825DF2"))
+		Bold(true).
+		Foreground(color(t.AccentText)).
+		Background(color(t.Accent)).
+		Reverse(t.Accent == "")
 
 	tableHeaderStyle = lipgloss.NewStyle().
-				Bold(true).
-				Padding(0, 1).
-				BorderStyle(lipgloss.NormalBorder()).
-				BorderForeground(lipgloss.Color("240")).
-				BorderBottom(true).
-				Bold(false)
+		Bold(true).
+		Padding(0, 1).
+		BorderStyle(lipgloss.NormalBorder()).
+		BorderForeground(color(t.Border)).
+		BorderBottom(true).
+		Bold(false)
 
-	tableCellStyle = lipgloss.NewStyle().
-			Padding(0, 1)
-)
+	tableVisualStyle = lipgloss.NewStyle().
+		Foreground(color(t.SelectionText)).
+		Background(color(t.Selection)).
+		Reverse(t.Selection == "")
+
+	searchMatchStyle = lipgloss.NewStyle().
+		Foreground(color(t.MatchText)).
+		Background(color(t.Match)).
+		Underline(t.Match == "")
+
+	// status line styles
+	statusBarStyle = lipgloss.NewStyle().
+		Foreground(color(t.StatusText)).
+		Background(color(t.StatusBar)).
+		Reverse(t.StatusBar == "")
+
+	statusModeStyle = lipgloss.NewStyle().
+		Bold(true).
+		Foreground(color(t.AccentText)).
+		Background(color(t.Accent))
+
+	statusErrorStyle = lipgloss.NewStyle().
+		Foreground(color(t.Error)).
+		Bold(t.Error == "")
+
+	// prompt styles
+	promptStyle = lipgloss.NewStyle().
+		PaddingLeft(1).
+		Foreground(color(t.Accent))
+}
+
+// color returns the color c of a theme, which is no color when empty.
+func color(c string) lipgloss.TerminalColor {
+	if c == "" {
+		return lipgloss.NoColor{}
+	}
+	return lipgloss.Color(c)
+}
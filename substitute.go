@@ -0,0 +1,272 @@
+package mdtt
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// substitution holds the state of a :s command that asks for confirmation
+// of each match.
+type substitution struct {
+	re     *regexp.Regexp
+	repl   string
+	global bool
+	// cells are the cells to process in order, cell is the index of the
+	// current one and offset is where the next match is searched from.
+	cells  []position
+	cell   int
+	offset int
+	// match is the location of the current match in the current cell.
+	match []int
+	count int
+}
+
+func cmdSubstitute(m *TableModel, c exCommand) (tea.Cmd, error) {
+	pat, repl, flags, err := splitSubstitute(c.args)
+	if err != nil {
+		return nil, err
+	}
+	if pat == "" {
+		// reuse the last search pattern, as vim does
+		pat = m.search.pattern
+	}
+	if pat == "" {
+		return nil, fmt.Errorf("E35: No previous regular expression")
+	}
+
+	opts := searchOptions{ignoreCase: m.searchOptions.ignoreCase, regex: true}
+	if strings.Contains(flags, "i") {
+		opts.ignoreCase = true
+	}
+	if strings.Contains(flags, "I") {
+		opts.ignoreCase = false
+	}
+	re, err := compileSearch(pat, opts)
+	if err != nil {
+		return nil, err
+	}
+	m.search.pattern = pat
+	m.search.re = re
+
+	m.sub = substitution{
+		re:     re,
+		repl:   expandTemplate(repl),
+		global: strings.Contains(flags, "g"),
+		cells:  m.substituteCells(c.rng),
+	}
+
+	m.saveSnapshot()
+	if strings.Contains(flags, "c") {
+		m.search.highlight = true
+		m.nextMatch()
+		if !m.gotoSubstitution() {
+			m.dropSnapshotIfUnchanged()
+			return nil, fmt.Errorf("E486: Pattern not found: %s", pat)
+		}
+		m.mode = SUBSTITUTE
+		return nil, nil
+	}
+
+	m.substituteAll()
+	if m.sub.count == 0 {
+		m.dropSnapshotIfUnchanged()
+		return nil, fmt.Errorf("E486: Pattern not found: %s", pat)
+	}
+	m.finishSubstitution()
+	return nil, nil
+}
+
+// substituteCells returns the cells in the range of a :s command, which is the
+// whole table for "%", the last visual selection for "'<,'>" and the current
+// column otherwise.
+func (m TableModel) substituteCells(rng string) []position {
+	if rng == "'<,'>" {
+		return m.lastSelectionCells()
+	}
+	var cells []position
+	for y := range m.rows {
+		for x := range m.cols {
+			if rng != "%" && x != m.cursor.x {
+				continue
+			}
+			cells = append(cells, position{x: x, y: y})
+		}
+	}
+	return cells
+}
+
+// updateSubstitute handles the answer to "replace with ... (y/n/a/q/l)?".
+func (m *TableModel) updateSubstitute(msg tea.KeyMsg) {
+	switch msg.String() {
+	case "y":
+		m.replaceMatch()
+	case "l":
+		m.replaceMatch()
+		m.finishSubstitution()
+		return
+	case "n":
+		m.sub.skip()
+	case "a":
+		m.substituteAll()
+		m.finishSubstitution()
+		return
+	case "q", "esc", "ctrl+c", "ctrl+[":
+		m.finishSubstitution()
+		return
+	default:
+		return
+	}
+
+	m.nextMatch()
+	if !m.gotoSubstitution() {
+		m.finishSubstitution()
+	}
+}
+
+// substituteAll replaces the remaining matches without confirmation.
+func (m *TableModel) substituteAll() {
+	for {
+		m.nextMatch()
+		if m.sub.cell >= len(m.sub.cells) {
+			return
+		}
+		m.replaceMatch()
+	}
+}
+
+// replaceMatch replaces the current match.
+func (m *TableModel) replaceMatch() {
+	p := m.sub.cells[m.sub.cell]
+	c := &m.rows[p.y][p.x]
+	v := c.value()
+
+	var dst []byte
+	dst = m.sub.re.ExpandString(dst, m.sub.repl, v, m.sub.match)
+	c.setValue(v[:m.sub.match[0]] + string(dst) + v[m.sub.match[1]:])
+	m.sub.count++
+
+	if !m.sub.global {
+		m.sub.cell++
+		m.sub.offset = 0
+		return
+	}
+	m.sub.offset = m.sub.match[0] + len(dst)
+	if m.sub.match[0] == m.sub.match[1] {
+		m.sub.offset++
+	}
+}
+
+// skip leaves the current match as is.
+func (s *substitution) skip() {
+	if !s.global {
+		s.cell++
+		s.offset = 0
+		return
+	}
+	s.offset = s.match[1]
+	if s.match[0] == s.match[1] {
+		s.offset++
+	}
+}
+
+// nextMatch finds the next match from the current cell and offset. The cell
+// index is past the last cell when there are no more matches.
+func (m *TableModel) nextMatch() {
+	s := &m.sub
+	s.match = nil
+	for ; s.cell < len(s.cells); s.cell, s.offset = s.cell+1, 0 {
+		p := s.cells[s.cell]
+		for _, loc := range s.re.FindAllStringSubmatchIndex(m.rows[p.y][p.x].value(), -1) {
+			if loc[0] >= s.offset {
+				s.match = loc
+				return
+			}
+		}
+	}
+}
+
+// gotoSubstitution moves the cursor to the current match. It returns false
+// when there are no more matches.
+func (m *TableModel) gotoSubstitution() bool {
+	if m.sub.cell >= len(m.sub.cells) {
+		return false
+	}
+	p := m.sub.cells[m.sub.cell]
+	m.gotoPosition(p)
+	v := m.rows[p.y][p.x].value()
+	m.setMessage(fmt.Sprintf("replace %q with %q (y/n/a/q/l)?",
+		v[m.sub.match[0]:m.sub.match[1]],
+		string(m.sub.re.ExpandString(nil, m.sub.repl, v, m.sub.match))))
+	return true
+}
+
+func (m *TableModel) finishSubstitution() {
+	if m.mode == SUBSTITUTE {
+		m.mode = NORMAL
+	}
+	m.dropSnapshotIfUnchanged()
+	m.updateWidths()
+	m.updateViewport()
+	m.setMessage(fmt.Sprintf("%d substitutions", m.sub.count))
+}
+
+// splitSubstitute splits "/pat/repl/flags" on the delimiter given by its first
+// character. The delimiter can be escaped with a backslash.
+func splitSubstitute(s string) (pat, repl, flags string, err error) {
+	if s == "" {
+		return "", "", "", fmt.Errorf("usage: s/{pattern}/{string}/[flags]")
+	}
+	delim := s[0]
+
+	var parts []string
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && s[i+1] == delim:
+			sb.WriteByte(delim)
+			i++
+		case s[i] == delim:
+			parts = append(parts, sb.String())
+			sb.Reset()
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	parts = append(parts, sb.String())
+
+	if len(parts) < 2 {
+		return "", "", "", fmt.Errorf("E486: Missing replacement string")
+	}
+	if len(parts) > 2 {
+		flags = parts[2]
+	}
+	return parts[0], parts[1], flags, nil
+}
+
+// expandTemplate converts a vim replacement string to a template for
+// regexp.Expand. "\1" to "\9" refer to capture groups and "&" or "\0" to the
+// whole match. "\&" and "\\" are a literal "&" and "\".
+func expandTemplate(repl string) string {
+	var sb strings.Builder
+	for i := 0; i < len(repl); i++ {
+		switch c := repl[i]; {
+		case c == '\\' && i+1 < len(repl):
+			i++
+			if d := repl[i]; d >= '0' && d <= '9' {
+				sb.WriteString("${" + string(d) + "}")
+			} else {
+				sb.WriteByte(d)
+			}
+		case c == '&':
+			sb.WriteString("${0}")
+		case c == '$':
+			sb.WriteString("$$")
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String()
+}
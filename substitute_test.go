@@ -0,0 +1,112 @@
+package mdtt
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestSplitSubstitute(t *testing.T) {
+	pat, repl, flags, err := splitSubstitute(`/a\/b/c/gi`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pat != "a/b" || repl != "c" || flags != "gi" {
+		t.Errorf("got %q %q %q", pat, repl, flags)
+	}
+
+	if _, _, _, err := splitSubstitute("/foo"); err == nil {
+		t.Error("expected an error for a missing replacement")
+	}
+}
+
+func TestExpandTemplate(t *testing.T) {
+	testCases := []struct {
+		repl string
+		want string
+	}{
+		{repl: "bar", want: "bar"},
+		{repl: `\1-\2`, want: "${1}-${2}"},
+		{repl: "[&]", want: "[${0}]"},
+		{repl: `\&$1`, want: "&$$1"},
+	}
+
+	for _, tc := range testCases {
+		if got := expandTemplate(tc.repl); got != tc.want {
+			t.Errorf("expandTemplate(%q) = %q, want %q", tc.repl, got, tc.want)
+		}
+	}
+}
+
+func TestSubstitute(t *testing.T) {
+	newTable := func() TableModel {
+		return NewTableModel(
+			WithColumns([]column{
+				{title: NewCell("product"), width: 5},
+				{title: NewCell("note"), width: 5},
+			}),
+			WithNaiveRows([]naiveRow{
+				{"Foo v1", "Foo Foo"},
+				{"Bar v2", "Foo"},
+				{"Foo v3", "Baz"},
+			}),
+		)
+	}
+
+	t.Run("capture groups", func(t *testing.T) {
+		m := newTable()
+		m.executeCommand(`%s/(\w+) v(\d)/\2:\1/`)
+		if got := m.rows[1][0].value(); got != "2:Bar" {
+			t.Errorf("got %q, want %q", got, "2:Bar")
+		}
+		m.undo()
+		if got := m.rows[1][0].value(); got != "Bar v2" {
+			t.Errorf("undo: got %q, want %q", got, "Bar v2")
+		}
+	})
+
+	t.Run("confirm", func(t *testing.T) {
+		m := newTable()
+		m.cursor.x = 1
+		m.executeCommand("s/foo/Qux/gci")
+		if m.mode != SUBSTITUTE || m.cursor != (cursor{x: 1, y: 0}) {
+			t.Fatalf("got mode %d cursor %+v", m.mode, m.cursor)
+		}
+
+		for _, k := range "nyq" {
+			m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{k}})
+		}
+		if m.mode != NORMAL {
+			t.Errorf("got mode %d, want NORMAL", m.mode)
+		}
+		if got := m.rows[0][1].value(); got != "Foo Qux" {
+			t.Errorf("got %q, want %q", got, "Foo Qux")
+		}
+		if got := m.rows[1][1].value(); got != "Foo" {
+			t.Errorf("got %q, want %q", got, "Foo")
+		}
+	})
+
+	t.Run("confirm all", func(t *testing.T) {
+		m := newTable()
+		m.executeCommand("%s/Foo/X/c")
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+		m, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+
+		want := []string{"Foo v1", "X Foo", "Bar v2", "X", "X v3", "Baz"}
+		var got []string
+		for _, r := range m.rows {
+			for _, c := range r {
+				got = append(got, c.value())
+			}
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("cell %d: got %q, want %q", i, got[i], want[i])
+			}
+		}
+		if m.sub.count != 3 {
+			t.Errorf("got %d substitutions, want 3", m.sub.count)
+		}
+	})
+}
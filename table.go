@@ -5,17 +5,17 @@ import (
 	"io"
 	"os"
 	"os/exec"
-	"time"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/x/editor"
 	"github.com/google/uuid"
-	"github.com/mattn/go-runewidth"
 )
 
 // Enum of Mode
@@ -25,6 +25,12 @@ const (
 	HEADER
 	HEADER_INSERT
 	HELP
+	COMMAND
+	SEARCH
+	SUBSTITUTE
+	VISUAL
+	VISUAL_LINE
+	VISUAL_COLUMN
 )
 
 // TableModel defines a state for the table widget.
@@ -35,7 +41,7 @@ type TableModel struct {
 	cursor   cursor
 	focus    bool
 	styles   tableStyles
-	prevKey  string
+	pending  pending
 	viewport viewport.Model
 	start    int
 	end      int
@@ -43,6 +49,38 @@ type TableModel struct {
 	// register is used to store the copied row or column.
 	register interface{}
 	help     help.Model
+	history  history
+	// cmdline is the input of the command line mode, which returns to
+	// cmdlineMode when it is closed.
+	cmdline     textinput.Model
+	cmdlineMode int
+	// message is shown in the status line until the next key press.
+	message       string
+	messageErr    bool
+	search        search
+	searchOptions searchOptions
+	// sub is the :s command waiting for confirmation in SUBSTITUTE mode.
+	sub substitution
+	// visual is the active selection in the visual modes and lastVisual the
+	// one used by the '<,'> range.
+	visual     selection
+	lastVisual selection
+	// source is the markdown text the table was parsed from, written back
+	// as is while the table is unedited, and offset its position in the
+	// markdown, -1 without source.
+	source string
+	offset int
+	// width and height are the size of the terminal, 0 until it is known.
+	// The columns from left on scroll to fit in the width, after the frozen
+	// first columns, and the rows from start on in the height.
+	width  int
+	height int
+	left   int
+	frozen int
+	// path, tableIndex and tableCount locate the table for the status bar.
+	path       string
+	tableIndex int
+	tableCount int
 }
 
 type cursor struct {
@@ -63,12 +101,12 @@ type column struct {
 }
 
 type rowRegister struct {
-	row
+	rows []row
 }
 type colRegister struct {
-	column
-	x, y  int
-	cells []cell
+	cols []column
+	// cells holds the body cells of each column.
+	cells [][]cell
 }
 
 type cellRegister struct {
@@ -76,11 +114,27 @@ type cellRegister struct {
 	cell cell
 }
 
+// blockRegister holds a rectangular range of body cells.
+type blockRegister struct {
+	cells []row
+}
+
 type quitMsg struct{}
 
-type delPrevKeyMsg struct{}
+// pickerMsg asks to return to the table picker.
+type pickerMsg struct{}
+
+// writeQuitMsg asks to write the table and quit without confirmation.
+type writeQuitMsg struct{}
+
+// discardMsg asks to quit without writing the table.
+type discardMsg struct{}
 
+// closeEditorMsg reports the text of the focused cell written in the
+// external editor, or why it could not be edited.
 type closeEditorMsg struct {
+	text string
+	err  error
 }
 type openEditorMsg struct {
 	path string
@@ -92,142 +146,56 @@ func quitCmd() tea.Cmd {
 	}
 }
 
-// keyMap defines keybindings. It satisfies to the help.keyMap interface, which
-// is used to render the menu.
-type keyMap struct {
-	lineUp       key.Binding
-	lineDown     key.Binding
-	right        key.Binding
-	left         key.Binding
-	addRowCol    key.Binding
-	delRowCol    key.Binding
-	yank         key.Binding
-	yankCell     key.Binding
-	clearCell    key.Binding
-	paste        key.Binding
-	pageUp       key.Binding
-	pageDown     key.Binding
-	halfPageUp   key.Binding
-	halfPageDown key.Binding
-	gotoTop      key.Binding
-	gotoBottom   key.Binding
-	insertMode   key.Binding
-	normalMode   key.Binding
-	quit         key.Binding
-	editor       key.Binding
-	help         key.Binding
-}
-
-// defaultKeyMap returns a default set of keybindings.
-func defaultKeyMap() keyMap {
-	const spacebar = " "
-	return keyMap{
-		lineUp: key.NewBinding(
-			key.WithKeys("up", "k"),
-			key.WithHelp("↑/k", "up"),
-		),
-		lineDown: key.NewBinding(
-			key.WithKeys("down", "j"),
-			key.WithHelp("↓/j", "down"),
-		),
-		right: key.NewBinding(
-			key.WithKeys("right", "l"),
-			key.WithHelp("→/l", "right"),
-		),
-		left: key.NewBinding(
-			key.WithKeys("left", "h"),
-			key.WithHelp("←/h", "left"),
-		),
-		addRowCol: key.NewBinding(
-			key.WithKeys("o"),
-			key.WithHelp("o/v+o", "add row/column"),
-		),
-		delRowCol: key.NewBinding(
-			key.WithKeys("d"),
-			key.WithHelp("dd/v+d", "delete row/column"),
-		),
-		clearCell: key.NewBinding(
-			key.WithKeys("x"),
-			key.WithHelp("x", "clear cell"),
-		),
-		yank: key.NewBinding(
-			key.WithKeys("y"),
-			key.WithHelp("yy/v+y", "copy row/column"),
-		),
-		yankCell: key.NewBinding(
-			key.WithKeys("."),
-			key.WithHelp("y.", "copy cell"),
-		),
-		paste: key.NewBinding(
-			key.WithKeys("p"),
-			key.WithHelp("p", "paste"),
-		),
-		pageUp: key.NewBinding(
-			key.WithKeys("b", "pgup"),
-			key.WithHelp("b/pgup", "page up"),
-		),
-		pageDown: key.NewBinding(
-			key.WithKeys("f", "pgdown", spacebar),
-			key.WithHelp("f/pgdn", "page down"),
-		),
-		halfPageUp: key.NewBinding(
-			key.WithKeys("ctrl+u"),
-			key.WithHelp("ctrl+u", "½ page up"),
-		),
-		halfPageDown: key.NewBinding(
-			key.WithKeys("ctrl+d"),
-			key.WithHelp("ctrl+d", "½ page down"),
-		),
-		gotoTop: key.NewBinding(
-			key.WithKeys("home", "g"),
-			key.WithHelp("g/home", "go to start"),
-		),
-		gotoBottom: key.NewBinding(
-			key.WithKeys("end", "G"),
-			key.WithHelp("G/end", "go to end"),
-		),
-		insertMode: key.NewBinding(
-			key.WithKeys("i"),
-			key.WithHelp("i", "insert mode"),
-		),
-		normalMode: key.NewBinding(
-			key.WithKeys("esc", "ctrl+c", "ctrl+["),
-			key.WithHelp("esc/ctrl-c", "normal mode"),
-		),
-		quit: key.NewBinding(
-			key.WithKeys("q"),
-			key.WithHelp("q", "quit"),
-		),
-		editor: key.NewBinding(
-			key.WithKeys("I"),
-			key.WithHelp("I", "open editor"),
-		),
-		help: key.NewBinding(
-			key.WithKeys("?"),
-			key.WithHelp("?", "help"),
-		),
+func pickerCmd() tea.Cmd {
+	return func() tea.Msg {
+		return pickerMsg{}
 	}
 }
 
-func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.help}
+func writeQuitCmd() tea.Cmd {
+	return func() tea.Msg {
+		return writeQuitMsg{}
+	}
 }
 
-func (k keyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{
-		{k.lineUp, k.lineDown, k.left, k.right, k.pageUp, k.pageDown,
-			k.halfPageUp, k.halfPageDown, k.gotoTop, k.gotoBottom},
-		{k.insertMode, k.editor, k.normalMode, k.addRowCol, k.delRowCol,
-			k.yank, k.paste, k.quit, k.help},
+func discardCmd() tea.Cmd {
+	return func() tea.Msg {
+		return discardMsg{}
 	}
 }
 
+// helpColumns splits the full help into columns that fit in the height of
+// the terminal, balancing their lengths.
+func (m TableModel) helpColumns() [][]key.Binding {
+	if m.height <= 0 {
+		return m.keys.FullHelp()
+	}
+
+	var bindings []key.Binding
+	for _, g := range m.keys.FullHelp() {
+		bindings = append(bindings, g...)
+	}
+	// the help is drawn in the frame of the table
+	height := max(m.height-2, 1)
+	ncols := (len(bindings) + height - 1) / height
+	height = (len(bindings) + ncols - 1) / ncols
+
+	var cols [][]key.Binding
+	for len(bindings) > 0 {
+		n := min(height, len(bindings))
+		cols = append(cols, bindings[:n])
+		bindings = bindings[n:]
+	}
+	return cols
+}
+
 // tableStyles contains style definitions for this list component. By default, these
 // values are generated by DefaultStyles.
 type tableStyles struct {
 	header   lipgloss.Style
 	cell     lipgloss.Style
 	selected lipgloss.Style
+	visual   lipgloss.Style
 }
 
 // defaultStyles returns a set of default style definitions for this table.
@@ -236,6 +204,7 @@ func defaultStyles() tableStyles {
 		selected: tableSelectedStyle,
 		header:   tableHeaderStyle,
 		cell:     tableCellStyle,
+		visual:   tableVisualStyle,
 	}
 }
 
@@ -256,15 +225,20 @@ func NewTableModel(opts ...TableOption) TableModel {
 		cursor:   cursor{0, 0},
 		viewport: viewport.New(0, 0),
 
-		keys:   defaultKeyMap(),
-		styles: defaultStyles(),
-		mode:   NORMAL,
-		help:   help.New(),
+		keys:    defaultKeyMap(),
+		styles:  defaultStyles(),
+		mode:    NORMAL,
+		help:    help.New(),
+		cmdline: newCommandLine(),
 	}
 
 	for _, opt := range opts {
 		opt(&m)
 	}
+	// a table without rows can only be edited from its header
+	if len(m.rows) == 0 {
+		m.mode = HEADER
+	}
 
 	m.updateViewport()
 	m.viewport.Height = m.viewport.Height - 1
@@ -338,77 +312,157 @@ func WithKeyMap(km keyMap) TableOption {
 func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 	var cmds []tea.Cmd
 
+	if msg, ok := msg.(tea.WindowSizeMsg); ok {
+		m.resize(msg.Width, msg.Height)
+		return m, nil
+	}
+
 	switch m.mode {
 	case NORMAL, HEADER:
 		switch msg := msg.(type) {
 		case widthMsg:
 			m.updateWidth(msg.width)
-		case delPrevKeyMsg:
-			m.setPrevKey("")
+			m.updateViewport()
 		case closeEditorMsg:
+			m.closeEditor(msg)
 			cmd := m.updateFocusedCell(msg)
 			cmds = append(cmds, cmd)
 		case tea.KeyMsg:
+			m.clearMessage()
+			if keyMatches(msg, m.keys.normalMode) {
+				m.pending.reset()
+				return m, nil
+			}
+			if m.pending.addDigit(msg) {
+				return m, nil
+			}
+			if !m.pending.push(msg, m.keys.bindings(normalScope)) {
+				return m, nil
+			}
+
+			n := m.pending.n()
 			switch {
-			case key.Matches(msg, m.keys.help):
+			case m.pending.is(m.keys.help):
 				m.enableAllHelp()
-			case key.Matches(msg, m.keys.quit):
+			case m.pending.is(m.keys.quit):
+				m.pending.reset()
 				return m, quitCmd()
-			case key.Matches(msg, m.keys.lineUp):
-				m.moveUp(1)
-			case key.Matches(msg, m.keys.lineDown):
-				m.moveDown(1)
-			case key.Matches(msg, m.keys.right):
-				m.moveRight(1)
-			case key.Matches(msg, m.keys.left):
-				m.moveLeft(1)
-			case key.Matches(msg, m.keys.addRowCol):
-				m.addEmpty()
+			case m.pending.is(m.keys.writeQuit):
+				m.pending.reset()
+				return m, writeQuitCmd()
+			case m.pending.is(m.keys.discard):
+				m.pending.reset()
+				return m, discardCmd()
+			case m.pending.is(m.keys.lineUp):
+				m.moveUp(n)
+			case m.pending.is(m.keys.lineDown):
+				m.moveDown(n)
+			case m.pending.is(m.keys.right):
+				m.moveRight(n)
+			case m.pending.is(m.keys.left):
+				m.moveLeft(n)
+			case m.pending.is(m.keys.addRowCol):
+				m.saveSnapshot()
+				m.addEmpty(n)
 				m.switchMode(INSERT)
 
-			case key.Matches(msg, m.keys.delRowCol):
+			case m.pending.is(m.keys.deleteRows):
 				if m.mode == HEADER {
-					return m, nil
+					break
 				}
-				cmd := m.delete()
-				cmds = append(cmds, cmd)
-			case key.Matches(msg, m.keys.clearCell):
-				m.clearCell()
-			case key.Matches(msg, m.keys.yank):
-				m.copy()
-			case key.Matches(msg, m.keys.yankCell):
+				m.deleteRows(n)
+			case m.pending.is(m.keys.clearCell):
+				m.clearCell(n)
+			case m.pending.is(m.keys.yankRows):
+				m.yankRows(n)
+			case m.pending.is(m.keys.yankCell):
 				m.copyCell()
 
-			case key.Matches(msg, m.keys.paste):
-				m.paste()
-
-			case key.Matches(msg, m.keys.pageUp):
-				m.moveUp(m.viewport.Height)
-			case key.Matches(msg, m.keys.pageDown):
-				m.moveDown(m.viewport.Height)
-			case key.Matches(msg, m.keys.halfPageUp):
-				m.moveUp(m.viewport.Height / 2)
-			case key.Matches(msg, m.keys.halfPageDown):
-				m.moveDown(m.viewport.Height / 2)
-			case key.Matches(msg, m.keys.lineDown):
-				m.moveDown(1)
-			case key.Matches(msg, m.keys.gotoTop):
-				m.gotoTop()
-			case key.Matches(msg, m.keys.gotoBottom):
-				m.gotoBottom()
-			case key.Matches(msg, m.keys.insertMode):
+			case m.pending.is(m.keys.paste):
+				m.paste(n)
+			case m.pending.is(m.keys.align):
+				m.cycleAlignment(m.cursor.x, m.cursor.x)
+			case m.pending.is(m.keys.moveRowUp):
+				m.moveRow(-n)
+			case m.pending.is(m.keys.moveRowDown):
+				m.moveRow(n)
+			case m.pending.is(m.keys.moveColLeft):
+				m.moveColumn(-n)
+			case m.pending.is(m.keys.moveColRight):
+				m.moveColumn(n)
+			case m.pending.is(m.keys.undo):
+				for range n {
+					m.undo()
+				}
+			case m.pending.is(m.keys.redo):
+				for range n {
+					m.redo()
+				}
+
+			case m.pending.is(m.keys.pageUp):
+				m.moveUp(m.viewport.Height * n)
+			case m.pending.is(m.keys.pageDown):
+				m.moveDown(m.viewport.Height * n)
+			case m.pending.is(m.keys.halfPageUp):
+				m.moveUp(m.viewport.Height / 2 * n)
+			case m.pending.is(m.keys.halfPageDown):
+				m.moveDown(m.viewport.Height / 2 * n)
+			case m.pending.is(m.keys.gotoTop):
+				if m.pending.count > 0 {
+					m.gotoRow(n)
+				} else {
+					m.gotoTop()
+				}
+			case m.pending.is(m.keys.gotoBottom):
+				if m.pending.count > 0 {
+					m.gotoRow(n)
+				} else {
+					m.gotoBottom()
+				}
+			case m.pending.is(m.keys.insertMode):
 				if len(m.cols) == 0 {
-					return m, nil
+					break
 				}
+				m.saveSnapshot()
 				if m.mode == HEADER {
 					m.switchMode(HEADER_INSERT)
 				} else {
 					m.switchMode(INSERT)
 				}
-			case key.Matches(msg, m.keys.editor):
+			case m.pending.is(m.keys.editor):
+				m.pending.reset()
 				return m, m.writeTmpFile()
+			case m.pending.is(m.keys.command):
+				m.pending.reset()
+				m.openCommandLine()
+				return m, textinput.Blink
+			case m.pending.is(m.keys.search):
+				m.pending.reset()
+				m.openSearch(false)
+				return m, textinput.Blink
+			case m.pending.is(m.keys.searchBack):
+				m.pending.reset()
+				m.openSearch(true)
+				return m, textinput.Blink
+			case m.pending.is(m.keys.searchNext):
+				for range n {
+					m.searchNext(false)
+				}
+			case m.pending.is(m.keys.searchPrev):
+				for range n {
+					m.searchNext(true)
+				}
+			case m.pending.is(m.keys.picker):
+				m.pending.reset()
+				return m, pickerCmd()
+			case m.pending.is(m.keys.visual):
+				m.startVisual(VISUAL_COLUMN, n)
+			case m.pending.is(m.keys.visualLine):
+				m.startVisual(VISUAL_LINE, n)
+			case m.pending.is(m.keys.visualBlock):
+				m.startVisual(VISUAL, n)
 			}
-			m.setPrevKey(msg.String())
+			m.pending.reset()
 		case openEditorMsg:
 			return m, m.openEditor(msg.path)
 		}
@@ -416,11 +470,11 @@ func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 		switch msg := msg.(type) {
 		case widthMsg:
 			m.updateWidth(msg.width)
-		case delPrevKeyMsg:
-			m.setPrevKey("")
+			m.updateViewport()
 		case tea.KeyMsg:
 			switch {
-			case key.Matches(msg, m.keys.normalMode):
+			case keyMatches(msg, m.keys.normalMode):
+				m.dropSnapshotIfUnchanged()
 				if m.mode == HEADER_INSERT {
 					m.switchMode(HEADER)
 				} else {
@@ -430,43 +484,91 @@ func (m TableModel) Update(msg tea.Msg) (TableModel, tea.Cmd) {
 				cmd := m.updateFocusedCell(msg)
 				cmds = append(cmds, cmd)
 			}
-			m.setPrevKey(msg.String())
+		}
+	case COMMAND, SEARCH:
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			switch msg.String() {
+			case "enter":
+				return m, m.submitCommandLine()
+			case "esc", "ctrl+c", "ctrl+[":
+				m.cancelCommandLine()
+			case "backspace":
+				if m.cmdline.Value() == "" {
+					m.cancelCommandLine()
+					return m, nil
+				}
+				fallthrough
+			default:
+				var cmd tea.Cmd
+				m.cmdline, cmd = m.cmdline.Update(msg)
+				cmds = append(cmds, cmd)
+				if m.mode == SEARCH {
+					m.updateSearch()
+				}
+			}
+		default:
+			var cmd tea.Cmd
+			m.cmdline, cmd = m.cmdline.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	case SUBSTITUTE:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			m.updateSubstitute(msg)
+		}
+	case VISUAL, VISUAL_LINE, VISUAL_COLUMN:
+		switch msg := msg.(type) {
+		case widthMsg:
+			m.updateWidth(msg.width)
+			m.updateViewport()
+		case tea.KeyMsg:
+			m.clearMessage()
+			cmds = append(cmds, m.updateVisual(msg))
 		}
 	case HELP:
 		switch msg := msg.(type) {
 		case tea.KeyMsg:
+			if !m.pending.push(msg, m.keys.bindings(helpScope)) {
+				return m, nil
+			}
 			switch {
-			case key.Matches(msg, m.keys.help):
+			case m.pending.is(m.keys.help, m.keys.normalMode):
 				m.disableAllHelp()
-			case key.Matches(msg, m.keys.quit):
+			case m.pending.is(m.keys.quit):
+				m.pending.reset()
 				return m, quitCmd()
 			}
+			m.pending.reset()
 		}
 	}
 
 	return m, tea.Batch(cmds...)
 }
 
-func (m *TableModel) clearCell() {
-	if m.mode == HEADER {
-		m.cols[m.cursor.x].title.setValue("")
-	} else if m.mode == NORMAL {
-		m.rows[m.cursor.y][m.cursor.x].setValue("")
+// clearCell clears n cells from the cursor to the right.
+func (m *TableModel) clearCell(n int) {
+	if len(m.cols) == 0 || (m.mode == NORMAL && len(m.rows) == 0) {
+		return
+	}
+	m.saveSnapshot()
+	for x := m.cursor.x; x < min(m.cursor.x+n, len(m.cols)); x++ {
+		if m.mode == HEADER {
+			m.cols[x].title.setValue("")
+		} else if m.mode == NORMAL {
+			m.rows[m.cursor.y][x].setValue("")
+		}
 	}
+	m.dropSnapshotIfUnchanged()
 	m.updateViewport()
 }
 
-func (m *TableModel) setPrevKey(s string) {
-	m.prevKey = s
-}
-
 func (m *TableModel) writeTmpFile() tea.Cmd {
 	tmppath := fmt.Sprintf("%s/mdtt_%s", os.TempDir(), uuid.New())
 	var focusedCell string
 	if m.mode == HEADER {
-		focusedCell = m.cols[m.cursor.x].title.value()
+		focusedCell = m.cols[m.cursor.x].title.view()
 	} else if m.mode == NORMAL {
-		focusedCell = m.rows[m.cursor.y][m.cursor.x].value()
+		focusedCell = m.rows[m.cursor.y][m.cursor.x].view()
 	}
 	cmd := exec.Command(
 		"sh",
@@ -479,7 +581,7 @@ func (m *TableModel) writeTmpFile() tea.Cmd {
 	return tea.ExecProcess(cmd, func(err error) tea.Msg {
 		if err != nil {
 			log.Debug(err)
-			return err
+			return closeEditorMsg{err: err}
 		}
 
 		return openEditorMsg{path: tmppath}
@@ -491,43 +593,142 @@ func (m *TableModel) openEditor(path string) tea.Cmd {
 	cmd, err := editor.Cmd("X", path)
 	if err != nil {
 		log.Debug(err)
-		return nil
+		return func() tea.Msg {
+			return closeEditorMsg{err: err}
+		}
 	}
 
 	return tea.ExecProcess(cmd, func(err error) tea.Msg {
 		if err != nil {
 			log.Debug(err)
-			return err
+			return closeEditorMsg{err: err}
 		}
 
 		f, err := os.Open(path)
 		if err != nil {
-			return err
+			return closeEditorMsg{err: err}
 		}
 		defer f.Close()
 
 		var b []byte
 		b, err = io.ReadAll(f)
 		if err != nil {
-			return err
+			return closeEditorMsg{err: err}
 		}
 
-		if m.mode == HEADER {
-			m.cols[m.cursor.x].title.setValue(string(b))
-			m.cols[m.cursor.x].title.setCursor(
-				len(m.cols[m.cursor.x].title.value()) - 1,
-			)
-		} else if m.mode == NORMAL {
-			m.rows[m.cursor.y][m.cursor.x].setValue(string(b))
-			m.rows[m.cursor.y][m.cursor.x].setCursor(
-				len(m.rows[m.cursor.y][m.cursor.x].value()) - 1,
-			)
-		}
+		// the lines of the file become the lines of the cell
+		return closeEditorMsg{text: joinLines(strings.TrimSuffix(string(b), "\n"))}
+	})
+}
 
-		m.updateWidth(0)
+// closeEditor sets the focused cell to the text written in the external
+// editor. The undo snapshot is only saved once the editor succeeded, so that
+// a failed editor leaves the table unmodified.
+func (m *TableModel) closeEditor(msg closeEditorMsg) {
+	if msg.err != nil {
+		m.setError(msg.err)
+		return
+	}
 
-		return closeEditorMsg{}
-	})
+	m.saveSnapshot()
+	if m.mode == HEADER {
+		m.cols[m.cursor.x].title.setValue(msg.text)
+	} else if m.mode == NORMAL {
+		m.rows[m.cursor.y][m.cursor.x].setValue(msg.text)
+	}
+	m.dropSnapshotIfUnchanged()
+	m.updateWidth(0)
+}
+
+func (m *TableModel) openCommandLine() {
+	m.cmdlineMode = m.mode
+	m.mode = COMMAND
+	m.cmdline.Reset()
+	m.cmdline.Focus()
+}
+
+func (m *TableModel) closeCommandLine() {
+	m.cmdline.Blur()
+	m.cmdline.Prompt = ":"
+	m.mode = m.cmdlineMode
+}
+
+// submitCommandLine runs the command or the search typed in the command line.
+func (m *TableModel) submitCommandLine() tea.Cmd {
+	input := m.cmdline.Value()
+	isSearch := m.mode == SEARCH
+	backward := m.cmdline.Prompt == "?"
+	m.closeCommandLine()
+
+	if isSearch {
+		m.confirmSearch(input, backward)
+		return nil
+	}
+	return m.executeCommand(input)
+}
+
+func (m *TableModel) cancelCommandLine() {
+	isSearch := m.mode == SEARCH
+	m.closeCommandLine()
+	if isSearch {
+		m.cancelSearch()
+	}
+}
+
+// baseMode returns the mode the command line was opened from while it is
+// open, and the current mode otherwise.
+func (m TableModel) baseMode() int {
+	if m.mode == COMMAND || m.mode == SEARCH {
+		return m.cmdlineMode
+	}
+	return m.mode
+}
+
+func (m *TableModel) setMessage(s string) {
+	m.message = s
+	m.messageErr = false
+}
+
+func (m *TableModel) setError(err error) {
+	m.message = err.Error()
+	m.messageErr = true
+}
+
+func (m *TableModel) clearMessage() {
+	m.message = ""
+	m.messageErr = false
+}
+
+// setAlignment sets the alignment of the columns x0 to x1.
+func (m *TableModel) setAlignment(x0, x1 int, a string) error {
+	switch a {
+	case "", "none":
+		a = "none"
+	case "left", "center", "right":
+	default:
+		return fmt.Errorf("E474: Invalid argument: align=%s", a)
+	}
+	if len(m.cols) == 0 {
+		return nil
+	}
+	m.saveSnapshot()
+	for x := x0; x <= x1; x++ {
+		m.cols[x].alignment = a
+	}
+	m.dropSnapshotIfUnchanged()
+	m.updateViewport()
+	return nil
+}
+
+// cycleAlignment sets the alignment of the columns x0 to x1 to the one that
+// follows the alignment of the first column.
+func (m *TableModel) cycleAlignment(x0, x1 int) {
+	if len(m.cols) == 0 {
+		return
+	}
+	a := nextAlignment(m.cols[x0].alignment)
+	m.setAlignment(x0, x1, a)
+	m.setMessage("align=" + a)
 }
 
 func (m *TableModel) enableAllHelp() {
@@ -557,7 +758,26 @@ func (m *TableModel) updateFocusedCell(msg tea.Msg) tea.Cmd {
 	return nil
 }
 
+// editedCell returns the cell at the cursor that is edited in mode, or nil
+// when mode is not an insert mode.
+func (m *TableModel) editedCell(mode int) *cell {
+	switch {
+	case mode == INSERT && 0 <= m.cursor.y && m.cursor.y < len(m.rows) &&
+		0 <= m.cursor.x && m.cursor.x < len(m.cols):
+		return &m.rows[m.cursor.y][m.cursor.x]
+	case mode == HEADER_INSERT && 0 <= m.cursor.x && m.cursor.x < len(m.cols):
+		return &m.cols[m.cursor.x].title
+	}
+	return nil
+}
+
 func (m *TableModel) switchMode(mode int) {
+	if c := m.editedCell(m.mode); c != nil {
+		c.stopEdit()
+	}
+	if c := m.editedCell(mode); c != nil {
+		c.startEdit()
+	}
 	m.mode = mode
 	if mode == INSERT {
 		m.styles.selected = lipgloss.NewStyle().Bold(true)
@@ -570,35 +790,36 @@ func (m *TableModel) switchMode(mode int) {
 func (m TableModel) updateWidth(w int) {
 	maxWidth := w
 	for _, r := range m.rows {
-		maxWidth = max(runewidth.StringWidth(r[m.cursor.x].value())+2, maxWidth)
+		maxWidth = max(cellWidth(r[m.cursor.x].value())+2, maxWidth)
 	}
-	maxWidth = max(runewidth.StringWidth(m.cols[m.cursor.x].title.value())+2, maxWidth)
+	maxWidth = max(cellWidth(m.cols[m.cursor.x].title.value())+2, maxWidth)
 	m.cols[m.cursor.x].width = maxWidth
 }
 
-func (m *TableModel) copy() {
-
-	if m.prevKey == "y" {
-		if len(m.rows) == 0 {
-			return
+// updateWidths fits every column to its contents.
+func (m TableModel) updateWidths() {
+	for x := range m.cols {
+		width := cellWidth(m.cols[x].title.value()) + widthPadding
+		for _, r := range m.rows {
+			width = max(cellWidth(r[x].value())+widthPadding, width)
 		}
-		m.copyRow()
-
-	} else if m.prevKey == "v" {
-		if len(m.cols) == 0 {
-			return
-		}
-		m.copyColumn()
-	} else {
-		m.setPrevKey("y")
+		m.cols[x].width = width
 	}
 }
 
-func (m *TableModel) copyCell() {
-	if m.prevKey != "y" {
+// yankRows copies n rows from the cursor to the register.
+func (m *TableModel) yankRows(n int) {
+	if len(m.rows) == 0 || m.mode == HEADER {
 		return
 	}
+	end := min(m.cursor.y+n, len(m.rows))
+	m.register = rowRegister{rows: copyRows(m.rows[m.cursor.y:end])}
+	if n > 1 {
+		m.setMessage(fmt.Sprintf("%d rows yanked", end-m.cursor.y))
+	}
+}
 
+func (m *TableModel) copyCell() {
 	if m.mode == HEADER {
 		m.register = cellRegister{
 			x:    m.cursor.x,
@@ -614,58 +835,64 @@ func (m *TableModel) copyCell() {
 	}
 }
 
-func (m *TableModel) copyColumn() {
-	var col column
-	col.title = NewCell(m.cols[m.cursor.x].title.value())
-	col.width = m.cols[m.cursor.x].width
-
-	var cells []cell
-	for _, r := range m.rows {
-		cells = append(cells, NewCell(r[m.cursor.x].value()))
+// paste pastes the register after the cursor. Rows and columns are pasted n
+// times.
+func (m *TableModel) paste(n int) {
+	if m.register != nil {
+		m.saveSnapshot()
 	}
-
-	m.register = colRegister{column: col, x: m.cursor.x, y: m.cursor.y, cells: cells}
-}
-
-func (m *TableModel) copyRow() {
-	var row row
-	for _, cell := range m.rows[m.cursor.y] {
-		row = append(row, NewCell(cell.value()))
-	}
-	m.register = rowRegister{row: row}
-}
-
-func (m *TableModel) paste() {
-	switch m.register.(type) {
+	switch reg := m.register.(type) {
 	case rowRegister:
-		if m.register != nil {
-			m.insertRow(m.cursor.y+1, m.register.(rowRegister).row)
-
-			var ro row
-			for _, cell := range m.register.(rowRegister).row {
-				ro = append(ro, NewCell(cell.value()))
+		idx := m.cursor.y + 1
+		if m.mode == HEADER || len(m.rows) == 0 {
+			idx = 0
+		}
+		for i := range n {
+			for j, r := range copyRows(reg.rows) {
+				m.insertRow(idx+i*len(reg.rows)+j, fitRow(r, len(m.cols)))
 			}
-			m.register = rowRegister{row: ro}
 		}
 		m.SetHeight(len(m.rows))
-		m.moveDown(1)
+		if m.mode == HEADER || idx == 0 {
+			m.switchMode(NORMAL)
+			m.cursor.y = 0
+		} else {
+			m.moveDown(1)
+		}
 	case colRegister:
-		if m.register != nil {
-			m.addColumn()
-			m.cols[m.cursor.x].title = NewCell(m.register.(colRegister).title.value())
-			for i := range len(m.rows) {
-				m.rows[i][m.cursor.x] = NewCell(m.register.(colRegister).cells[i].value())
+		for i := range n {
+			cols := copyColumns(reg.cols)
+			for j := range cols {
+				var cells []cell
+				for y := range m.rows {
+					if y < len(reg.cells[j]) {
+						cells = append(cells, NewCell(reg.cells[j][y].value()))
+					} else {
+						cells = append(cells, NewCell(""))
+					}
+				}
+				m.insertColumn(m.cursor.x+1+i*len(cols)+j, cols[j], cells)
 			}
 		}
-		m.updateWidth(0)
-	case cellRegister:
-		if m.register != nil {
-			if m.mode == HEADER {
-				m.cols[m.cursor.x].title = NewCell(m.register.(cellRegister).cell.value())
-			} else if m.mode == NORMAL {
-				m.rows[m.cursor.y][m.cursor.x] = NewCell(m.register.(cellRegister).cell.value())
+		m.moveRight(1)
+	case blockRegister:
+		if m.mode == HEADER {
+			return
+		}
+		for y, r := range reg.cells {
+			for x, c := range r {
+				if m.cursor.y+y < len(m.rows) && m.cursor.x+x < len(m.cols) {
+					m.rows[m.cursor.y+y][m.cursor.x+x] = NewCell(c.value())
+				}
 			}
 		}
+		m.updateWidths()
+	case cellRegister:
+		if m.mode == HEADER {
+			m.cols[m.cursor.x].title = NewCell(reg.cell.value())
+		} else if m.mode == NORMAL {
+			m.rows[m.cursor.y][m.cursor.x] = NewCell(reg.cell.value())
+		}
 		m.updateWidth(0)
 	default:
 		return
@@ -677,33 +904,63 @@ func (m *TableModel) paste() {
 // View renders the component.
 func (m TableModel) View() string {
 	if m.mode == HELP {
-		return tableFrameStyle.Render(m.help.View(m.keys))
+		return drawFrame(tableFrameStyle, m.help.FullHelpView(m.helpColumns()))
 	}
-	return tableFrameStyle.Render(m.headersView()+"\n"+m.viewport.View()) +
+	return drawFrame(tableFrameStyle, m.headersView()+"\n"+m.viewport.View()) +
+		"\n" + m.statusBarView() +
+		"\n" + m.statusView() +
 		"\n " + m.help.View(m.keys)
 }
 
+// statusView renders the line below the table frame, which holds either the
+// command line or the latest message, cut to the width of the terminal.
+func (m TableModel) statusView() string {
+	var s string
+	switch {
+	case m.mode == COMMAND || m.mode == SEARCH:
+		s = m.cmdline.View()
+	case m.mode == SUBSTITUTE:
+		s = promptStyle.Render(m.message)
+	case m.message == "" && m.pending.keys != "":
+		s = statusMessageStyle.Render(m.pending.keys)
+	case m.messageErr:
+		s = statusErrorStyle.Render(m.message)
+	default:
+		s = statusMessageStyle.Render(m.message)
+	}
+
+	if m.width > 0 {
+		s = truncate(s, m.width, ellipsis)
+	}
+	return s
+}
+
 // updateViewport updates the list content based on the previously defined
 // columns and rows.
 func (m *TableModel) updateViewport() {
-	renderedRows := make([]string, 0, len(m.rows))
-
-	// Render only rows from: m.cursor-m.viewport.Height to: m.cursor+m.viewport.Height
-	// Constant runtime, independent of number of rows in a table.
-	// Limits the number of renderedRows to a maximum of 2*m.viewport.Height
-	if m.cursor.y >= 0 {
-		m.start = clamp(m.cursor.y-m.viewport.Height, 0, m.cursor.y)
-	} else {
-		m.start = 0
+	m.scrollColumns()
+	m.scrollRows()
+
+	// Render only the rows from m.start that fit in the viewport, so that the
+	// runtime does not depend on the number of rows in the table.
+	var height int
+	if m.height > 0 {
+		height = m.rowsHeight()
+	}
+	var lines []string
+	m.end = m.start
+	for m.end < len(m.rows) && (height == 0 || len(lines) < height) {
+		lines = append(lines, strings.Split(m.renderRow(m.end), "\n")...)
+		m.end++
 	}
-	m.end = clamp(m.cursor.y+m.viewport.Height, m.cursor.y, len(m.rows))
-	for i := m.start; i < m.end; i++ {
-		renderedRows = append(renderedRows, m.renderRow(i))
+	if height > 0 && len(lines) > height {
+		// the last row is cut by the bottom of the terminal
+		lines = lines[:height]
 	}
 
-	m.viewport.SetContent(
-		lipgloss.JoinVertical(lipgloss.Left, renderedRows...),
-	)
+	m.viewport.Height = len(lines)
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+	m.viewport.SetYOffset(0)
 }
 
 // SelectedRow returns the selected row.
@@ -726,49 +983,43 @@ func (m *TableModel) SetRows(r []row) {
 	m.rows = r
 }
 
-func (m *TableModel) addEmpty() {
-	if m.prevKey == "v" {
-		m.addColumn()
-		return
-	}
-	newRow := make(row, len(m.cols))
-	for i := range m.cols {
-		newRow[i] = NewCell("")
-	}
-
+// addEmpty adds n empty rows below the cursor and moves to the first one.
+func (m *TableModel) addEmpty(n int) {
+	idx := m.cursor.y + 1
 	if m.mode == HEADER || len(m.rows) == 0 {
-		m.insertRow(0, newRow)
-	} else {
-		m.insertRow(m.cursor.y+1, newRow)
+		idx = 0
+	}
+	for range n {
+		newRow := make(row, len(m.cols))
+		for i := range m.cols {
+			newRow[i] = NewCell("")
+		}
+		m.insertRow(idx, newRow)
 	}
 	m.SetHeight(len(m.rows))
 	m.moveDown(1)
 }
 
-func (m *TableModel) delete() tea.Cmd {
-	if m.prevKey == "d" {
-		if len(m.rows) == 0 {
-			return nil
-		} else if len(m.rows) == 1 {
-			m.switchMode(HEADER)
-		}
-		m.copyRow()
-		m.deleteRow(clamp(m.cursor.y, 0, len(m.rows)-1))
-		m.cursor.y = clamp(m.cursor.y, 0, len(m.rows)-1)
-		m.SetHeight(len(m.rows))
-		m.updateViewport()
-		return clearPrevKeyCmd()
-	} else if m.prevKey == "v" {
-		if len(m.cols) == 0 {
-			return nil
-		}
-		m.copyColumn()
-		m.deleteColumn(m.cursor.x)
-		m.cursor.x = clamp(m.cursor.x, 0, len(m.cols)-1)
-		m.updateViewport()
+// deleteRows deletes n rows from the cursor and copies them to the register.
+func (m *TableModel) deleteRows(n int) {
+	if len(m.rows) == 0 {
+		return
 	}
-
-	return clearPrevKeyCmd()
+	m.saveSnapshot()
+	m.yankRows(n)
+	end := min(m.cursor.y+n, len(m.rows))
+	for y := end - 1; y >= m.cursor.y; y-- {
+		m.deleteRow(y)
+	}
+	if n > 1 {
+		m.setMessage(fmt.Sprintf("%d rows deleted", end-m.cursor.y))
+	}
+	if len(m.rows) == 0 {
+		m.switchMode(HEADER)
+	}
+	m.cursor.y = max(clamp(m.cursor.y, 0, len(m.rows)-1), 0)
+	m.SetHeight(len(m.rows))
+	m.updateViewport()
 }
 
 func (m *TableModel) addColumn() {
@@ -779,11 +1030,24 @@ func (m *TableModel) addColumn() {
 	}
 	m.SetRows(rows)
 
-	newCol := insertCol(m.cols, m.cursor.x+1, column{title: NewCell(""), width: 4})
+	// a new column is aligned like the one it is added after
+	var alignment string
+	if len(m.cols) > 0 {
+		alignment = m.cols[m.cursor.x].alignment
+	}
+	newCol := insertCol(m.cols, m.cursor.x+1, column{title: NewCell(""), width: 4, alignment: alignment})
 	m.SetColumns(newCol)
 	m.moveRight(1)
 }
 
+// insertColumn inserts col at idx with cells as its body cells.
+func (m *TableModel) insertColumn(idx int, col column, cells []cell) {
+	for i := range m.rows {
+		m.rows[i] = insertCell(m.rows[i], idx, cells[i])
+	}
+	m.SetColumns(insertCol(m.cols, idx, col))
+}
+
 // SetColumns sets a new columns state.
 func (m *TableModel) SetColumns(c []column) {
 	m.cols = c
@@ -818,26 +1082,17 @@ func (m TableModel) Cursor() int {
 
 // SetCursor sets the cursor.y position in the table.
 func (m *TableModel) SetCursor(n int) {
-	m.cursor.y = clamp(n, 0, len(m.rows)-1)
+	m.cursor.y = max(clamp(n, 0, len(m.rows)-1), 0)
 	m.updateViewport()
 }
 
 // moveUp moves the selection up by any number of rows.
 // It can not go above the first row.
 func (m *TableModel) moveUp(n int) {
-	if m.cursor.y == 0 {
+	if m.cursor.y == 0 && !isVisual(m.mode) {
 		m.switchMode(HEADER)
 	}
-	m.cursor.y = clamp(m.cursor.y-n, 0, len(m.rows)-1)
-
-	switch {
-	case m.start == 0:
-		m.viewport.SetYOffset(clamp(m.viewport.YOffset, 0, m.cursor.y))
-	case m.start < m.viewport.Height:
-		m.viewport.SetYOffset(clamp(m.viewport.YOffset+n, 0, m.cursor.y))
-	case m.viewport.YOffset >= 1:
-		m.viewport.YOffset = clamp(m.viewport.YOffset+n, 1, m.viewport.Height)
-	}
+	m.cursor.y = max(clamp(m.cursor.y-n, 0, len(m.rows)-1), 0)
 	m.updateViewport()
 }
 
@@ -850,18 +1105,8 @@ func (m *TableModel) moveDown(n int) {
 		return
 	}
 
-	m.cursor.y = clamp(m.cursor.y+n, 0, len(m.rows)-1)
+	m.cursor.y = max(clamp(m.cursor.y+n, 0, len(m.rows)-1), 0)
 	m.updateViewport()
-
-	switch {
-	case m.end == len(m.rows):
-		m.viewport.SetYOffset(clamp(m.viewport.YOffset-n, 1, m.viewport.Height))
-	case m.cursor.y > (m.end-m.start)/2:
-		m.viewport.SetYOffset(clamp(m.viewport.YOffset-n, 1, m.cursor.y))
-	case m.viewport.YOffset > 1:
-	case m.cursor.y > m.viewport.YOffset+m.viewport.Height-1:
-		m.viewport.SetYOffset(clamp(m.viewport.YOffset+1, 0, 1))
-	}
 }
 
 // moveRight moves the selection right by any number of rows.
@@ -886,62 +1131,124 @@ func (m *TableModel) gotoBottom() {
 	m.moveDown(len(m.rows))
 }
 
+// gotoRow moves the selection to the n-th row counted from 1.
+// Row 0 is the header.
+func (m *TableModel) gotoRow(n int) {
+	if n <= 0 || len(m.rows) == 0 {
+		m.moveUp(m.cursor.y)
+		if m.mode != HEADER {
+			m.switchMode(HEADER)
+		}
+		return
+	}
+
+	if m.mode == HEADER {
+		m.moveDown(1)
+	}
+	y := clamp(n-1, 0, len(m.rows)-1)
+	if y > m.cursor.y {
+		m.moveDown(y - m.cursor.y)
+	} else if y < m.cursor.y {
+		m.moveUp(m.cursor.y - y)
+	}
+}
+
 func (m TableModel) headersView() string {
-	var s = make([]string, 0, len(m.cols))
-	for i, col := range m.cols {
-		var style lipgloss.Style
-		if i == m.cursor.x && m.mode == HEADER {
-			style = m.styles.selected.
-				Copy().
-				PaddingRight(col.width - len(col.title.value()))
-		} else {
-			style = lipgloss.NewStyle().Width(col.width).MaxWidth(col.width).Inline(true)
+	var cells = make([]cellView, 0, len(m.cols))
+	var indicators = make([]string, 0, len(m.cols))
+	mode := m.baseMode()
+	if isVisual(mode) && m.visual.header {
+		// the cursor of a selection started from the header stays there
+		mode = HEADER
+	}
+	// the line below the header marks the alignments, so it is drawn here
+	// instead of as the border of the header cells
+	header := m.styles.header.Copy().BorderBottom(false)
+	border := lipgloss.NewStyle().Foreground(m.styles.header.GetBorderBottomForeground())
+	for _, cv := range m.visibleColumns() {
+		i, col := cv.x, m.cols[cv.x]
+		c := cellView{col: cv, frame: header}
+		if i == m.cursor.x && mode == HEADER {
+			c.text = m.styles.selected
+		} else if m.isColumnSelected(i) {
+			c.text = m.styles.visual
 		}
 
-		var renderedCell string
-		if i == m.cursor.x && m.mode == HEADER_INSERT {
-			renderedCell = style.Render(col.title.view())
+		if i == m.cursor.x && (mode == HEADER || mode == HEADER_INSERT) {
+			c.lines = strings.Split(col.title.view(), "\n")
 		} else {
-			renderedCell = style.Render(col.title.value())
+			c.lines = cellLines(m.highlightMatches(col.title.value()))
 		}
-		s = append(s, m.styles.header.Render(renderedCell))
+		cells = append(cells, c)
+		indicators = append(indicators,
+			border.Render(alignmentIndicator(col.alignment, cv.width+header.GetHorizontalFrameSize())))
 	}
-	return lipgloss.JoinHorizontal(lipgloss.Left, s...)
+	return m.joinCells(cells) + "\n" + strings.Join(indicators, "")
 }
 
 func (m *TableModel) renderRow(rowID int) string {
-	var s = make([]string, 0, len(m.cols))
-	for i, cell := range m.rows[rowID] {
-		style := lipgloss.NewStyle().
-			Width(m.cols[i].width).
-			MaxWidth(m.cols[i].width)
-
-		var renderedCell string
+	var cells = make([]cellView, 0, len(m.cols))
+	mode := m.baseMode()
+	for _, cv := range m.visibleColumns() {
+		i, cell := cv.x, m.rows[rowID][cv.x]
 		isSelected := i == m.cursor.x &&
 			rowID == m.cursor.y &&
-			m.mode != HEADER &&
-			m.mode != HEADER_INSERT
-		isInsertMode := m.mode == INSERT
+			mode != HEADER &&
+			mode != HEADER_INSERT &&
+			!(isVisual(mode) && m.visual.header)
+		isInsertMode := mode == INSERT
 
-		var value string
+		// <br> breaks the lines of a cell, making the row taller
+		c := cellView{col: cv, frame: m.styles.cell}
 		if isInsertMode && isSelected {
-			value = cell.view()
+			c.lines = strings.Split(cell.view(), "\n")
+		} else if isSelected {
+			c.lines = cellLines(cell.value())
 		} else {
-			value = cell.value()
+			c.lines = cellLines(m.highlightMatches(cell.value()))
 		}
 
 		if isSelected {
-			renderedCell = m.styles.selected.Render(style.Render(value))
-		} else {
-			renderedCell = m.styles.cell.Render(style.Render(value))
+			c.text = m.styles.selected
+		} else if m.isSelected(i, rowID) {
+			c.frame = m.styles.cell.Copy().Inherit(m.styles.visual)
 		}
-
-		s = append(s, renderedCell)
+		cells = append(cells, c)
 	}
 
-	row := lipgloss.JoinHorizontal(lipgloss.Left, s...)
+	return m.joinCells(cells)
+}
 
-	return row
+// cellView is a cell to render: its column, its lines, the style of its text
+// and the style around it.
+type cellView struct {
+	col   columnView
+	lines []string
+	text  lipgloss.Style
+	frame lipgloss.Style
+}
+
+// joinCells renders the cells of a row side by side. The lines are padded to
+// the widths of the columns here rather than by lipgloss, which measures an
+// emoji sequence rune by rune, so that wide characters stay aligned. The
+// lines of a cut column end with an ellipsis.
+func (m TableModel) joinCells(cells []cellView) string {
+	var height int
+	for _, c := range cells {
+		height = max(len(c.lines), height)
+	}
+
+	lines := make([]string, height)
+	for _, c := range cells {
+		for y := range lines {
+			var l string
+			if y < len(c.lines) {
+				l = truncate(c.lines[y], c.col.width, ellipsis)
+			}
+			lines[y] += c.frame.Render(c.text.Render(alignLine(l, c.col.width, m.cols[c.col.x].alignment)))
+		}
+	}
+	return strings.Join(lines, "\n")
 }
 
 func (m *TableModel) insertRow(idx int, ro row) {
@@ -949,6 +1256,15 @@ func (m *TableModel) insertRow(idx int, ro row) {
 	m.SetRows(rows)
 }
 
+// fitRow pads r with empty cells or cuts it to n cells, for rows yanked
+// before columns were added or deleted.
+func fitRow(r row, n int) row {
+	for len(r) < n {
+		r = append(r, NewCell(""))
+	}
+	return r[:n]
+}
+
 func (m *TableModel) deleteRow(idx int) {
 	rows := append(m.rows[:idx], m.rows[idx+1:]...)
 	m.SetRows(rows)
@@ -976,9 +1292,3 @@ func insertCol(c []column, idx int, col column) []column {
 func deleteCell(r row, idx int) row {
 	return append(r[:idx], r[idx+1:]...)
 }
-
-func clearPrevKeyCmd() tea.Cmd {
-	return tea.Tick(time.Millisecond*500, func(t time.Time) tea.Msg {
-		return delPrevKeyMsg{}
-	})
-}
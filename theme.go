@@ -0,0 +1,206 @@
+package mdtt
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Theme holds the colors of the UI. A color is either a hex color such as
+// "#825DF2" or an ANSI 256 color number such as "240". The elements without
+// colors are told apart by attributes, such as reversed colors for the
+// cursor.
+type Theme struct {
+	// Base is the theme the colors left empty are taken from, in a user
+	// theme of the config file.
+	Base string `toml:"base"`
+	// Accent is the background of the cursor and of the mode in the status
+	// bar, and the color of the prompts.
+	Accent string `toml:"accent"`
+	// AccentText is the color of the text on the accent color.
+	AccentText string `toml:"accent_text"`
+	// Border is the color of the table frame and of the header rule.
+	Border string `toml:"border"`
+	// Title is the color of the headers in the table list.
+	Title string `toml:"title"`
+	// Selection is the background of the visual selection.
+	Selection     string `toml:"selection"`
+	SelectionText string `toml:"selection_text"`
+	// Match is the background of the search matches.
+	Match     string `toml:"match"`
+	MatchText string `toml:"match_text"`
+	// StatusBar is the background of the status bar.
+	StatusBar  string `toml:"status_bar"`
+	StatusText string `toml:"status_text"`
+	// Error is the color of the error messages.
+	Error string `toml:"error"`
+}
+
+// Names of the built-in themes. ThemeAuto is the dark or the light theme
+// depending on the background of the terminal.
+const (
+	ThemeAuto         = "auto"
+	ThemeDark         = "dark"
+	ThemeLight        = "light"
+	ThemeHighContrast = "high-contrast"
+	ThemeMonochrome   = "monochrome"
+)
+
+// themes are the built-in themes.
+var themes = map[string]Theme{
+	ThemeDark: {
+		Accent:        "#825DF2",
+		AccentText:    "#FFFFFF",
+		Border:        "240",
+		Title:         "249",
+		Selection:     "#4B3B8F",
+		SelectionText: "#FFFFFF",
+		Match:         "#F2C94C",
+		MatchText:     "#000000",
+		StatusBar:     "236",
+		StatusText:    "250",
+		Error:         "9",
+	},
+	ThemeLight: {
+		Accent:        "#6A45E0",
+		AccentText:    "#FFFFFF",
+		Border:        "248",
+		Title:         "238",
+		Selection:     "#D8CCFB",
+		SelectionText: "#1C1C1C",
+		Match:         "#F2C94C",
+		MatchText:     "#000000",
+		StatusBar:     "254",
+		StatusText:    "238",
+		Error:         "#C62828",
+	},
+	ThemeHighContrast: {
+		Accent:        "#FFFF00",
+		AccentText:    "#000000",
+		Border:        "#FFFFFF",
+		Title:         "#FFFFFF",
+		Selection:     "#00FFFF",
+		SelectionText: "#000000",
+		Match:         "#FF00FF",
+		MatchText:     "#000000",
+		StatusBar:     "#FFFFFF",
+		StatusText:    "#000000",
+		Error:         "#FF5555",
+	},
+	ThemeMonochrome: {},
+}
+
+// ThemeNames returns the names of the built-in themes.
+func ThemeNames() []string {
+	names := []string{ThemeAuto}
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names[1:])
+	return names
+}
+
+// themeColor is a color of a theme with the name it has in the config file.
+type themeColor struct {
+	name  string
+	color *string
+}
+
+// colors returns the colors of the theme.
+func (t *Theme) colors() []themeColor {
+	return []themeColor{
+		{"accent", &t.Accent},
+		{"accent_text", &t.AccentText},
+		{"border", &t.Border},
+		{"title", &t.Title},
+		{"selection", &t.Selection},
+		{"selection_text", &t.SelectionText},
+		{"match", &t.Match},
+		{"match_text", &t.MatchText},
+		{"status_bar", &t.StatusBar},
+		{"status_text", &t.StatusText},
+		{"error", &t.Error},
+	}
+}
+
+// hexColor matches the hex colors of themes.
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validColor reports whether c is a hex color or an ANSI 256 color number.
+func validColor(c string) bool {
+	if strings.HasPrefix(c, "#") {
+		return hexColor.MatchString(c)
+	}
+	n, err := strconv.Atoi(c)
+	return err == nil && 0 <= n && n <= 255
+}
+
+// resolveTheme returns the theme called name with the colors it takes from
+// its base theme. seen are the user themes it is the base of, to report
+// cycles.
+func (c Config) resolveTheme(name string, seen []string) (Theme, error) {
+	t, ok := c.Themes[name]
+	if !ok {
+		builtin, ok := themes[name]
+		if !ok {
+			return Theme{}, fmt.Errorf("unknown theme %q", name)
+		}
+		return builtin, nil
+	}
+	if slices.Contains(seen, name) {
+		return Theme{}, fmt.Errorf("theme %q is based on itself", name)
+	}
+
+	for _, tc := range t.colors() {
+		if *tc.color != "" && !validColor(*tc.color) {
+			return Theme{}, fmt.Errorf("theme %q: invalid color %q for %s", name, *tc.color, tc.name)
+		}
+	}
+
+	base := t.Base
+	if base == "" {
+		base = ThemeDark
+	}
+	var b Theme
+	if builtin, ok := themes[base]; ok && base == name {
+		// a user theme named after a built-in one changes its colors
+		b = builtin
+	} else {
+		var err error
+		if b, err = c.resolveTheme(base, append(seen, name)); err != nil {
+			return Theme{}, err
+		}
+	}
+	bc := b.colors()
+	for i, tc := range t.colors() {
+		if *tc.color == "" {
+			*tc.color = *bc[i].color
+		}
+	}
+	t.Base = ""
+	return t, nil
+}
+
+// Theme returns the theme named in the config, or the one called name when
+// it is not empty, which is a built-in theme or one of the config. "auto" is
+// the dark or the light theme as reported by dark, or the monochrome one
+// when noColor is set. dark is not called otherwise, since asking the
+// terminal for its background takes time.
+func (c Config) Theme(name string, dark func() bool, noColor bool) (Theme, error) {
+	if name == "" {
+		name = c.ThemeName
+	}
+	if name == "" || name == ThemeAuto {
+		switch {
+		case noColor:
+			name = ThemeMonochrome
+		case dark():
+			name = ThemeDark
+		default:
+			name = ThemeLight
+		}
+	}
+	return c.resolveTheme(name, nil)
+}
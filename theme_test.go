@@ -0,0 +1,100 @@
+package mdtt
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTheme(t *testing.T) {
+	config := Config{
+		ThemeName: "solarized",
+		Themes: map[string]Theme{
+			"solarized": {Accent: "#268BD2", Border: "#586E75"},
+			"paper":     {Base: ThemeLight, Accent: "#000"},
+			"dark":      {Accent: "#FF0000"},
+			"loop":      {Base: "pool"},
+			"pool":      {Base: "loop"},
+			"invalid":   {Accent: "purple"},
+			"missing":   {Base: "sepia"},
+		},
+	}
+	dark := themes[ThemeDark]
+	dark.Accent = "#FF0000"
+	solarized := dark
+	solarized.Accent, solarized.Border = "#268BD2", "#586E75"
+	paper := themes[ThemeLight]
+	paper.Accent = "#000"
+
+	testCases := []struct {
+		name    string
+		config  Config
+		dark    bool
+		noColor bool
+		want    Theme
+		err     string
+	}{
+		{name: "", config: Config{}, dark: true, want: themes[ThemeDark]},
+		{name: "auto", config: Config{}, dark: false, want: themes[ThemeLight]},
+		{name: "auto", config: Config{}, dark: true, noColor: true, want: themes[ThemeMonochrome]},
+		{name: "high-contrast", config: Config{}, noColor: true, want: themes[ThemeHighContrast]},
+		{name: "", config: config, want: solarized},
+		{name: "paper", config: config, want: paper},
+		{name: "dark", config: config, want: dark},
+		{name: "loop", config: config, err: `theme "loop" is based on itself`},
+		{name: "invalid", config: config, err: `theme "invalid": invalid color "purple" for accent`},
+		{name: "missing", config: config, err: `unknown theme "sepia"`},
+		{name: "sepia", config: config, err: `unknown theme "sepia"`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.config.Theme(tc.name, func() bool { return tc.dark }, tc.noColor)
+			var gotErr string
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tc.err {
+				t.Fatalf("got error %q, want %q", gotErr, tc.err)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("differs: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidColor(t *testing.T) {
+	for c, want := range map[string]bool{
+		"#825DF2": true,
+		"#fff":    true,
+		"240":     true,
+		"0":       true,
+		"256":     false,
+		"-1":      false,
+		"#82":     false,
+		"#GGGGGG": false,
+		"purple":  false,
+	} {
+		if got := validColor(c); got != want {
+			t.Errorf("validColor(%q) = %v, want %v", c, got, want)
+		}
+	}
+}
+
+func TestSetTheme(t *testing.T) {
+	t.Cleanup(func() { SetTheme(themes[ThemeDark]) })
+
+	SetTheme(themes[ThemeMonochrome])
+	if !tableSelectedStyle.GetReverse() || !tableVisualStyle.GetReverse() || !searchMatchStyle.GetUnderline() {
+		t.Errorf("the cursor, the selection and the matches are not told apart without colors")
+	}
+
+	SetTheme(themes[ThemeHighContrast])
+	if tableSelectedStyle.GetReverse() {
+		t.Errorf("the cursor is reversed with colors")
+	}
+	if got := tableSelectedStyle.GetBackground(); got != color("#FFFF00") {
+		t.Errorf("got cursor background %v", got)
+	}
+}
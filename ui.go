@@ -18,6 +18,15 @@ type Model struct {
 	list    headerList
 	fpath   string
 	inplace bool
+	// format is the format the tables are printed in, markdown by default.
+	format string
+	// confirmQuit is set while asking whether to save a modified table.
+	confirmQuit bool
+	// size is the size of the terminal, given to every table and to the
+	// table list.
+	size tea.WindowSizeMsg
+	// keys are the key bindings of every table.
+	keys keyMap
 }
 
 type Option func(*Model) error
@@ -26,16 +35,61 @@ func (m Model) Init() tea.Cmd { return nil }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
+	if m.confirmQuit {
+		return m.updateConfirmQuit(msg)
+	}
+
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.resize(msg)
+		return m, nil
 	case selectMsg:
 		m.preview = false
 		m.choose = msg.idx
 		m.table = m.tables[msg.idx]
+	case pickerMsg:
+		if len(m.tables) < 2 {
+			m.table.setError(fmt.Errorf("there are no other tables"))
+			return m, nil
+		}
+		m.syncTable()
+		m.preview = true
+		m.list = NewHeaderList(WithTables(m.tables))
+		m.list.list.Select(m.choose)
+		m.list, _ = m.list.update(m.size)
+		return m, nil
 	case quitMsg:
+		m.syncTable()
+		if m.modified() {
+			m.confirmQuit = true
+			return m, nil
+		}
 		if !m.preview {
-			Write(m)
+			if err := m.write(); err != nil {
+				m.table.setError(err)
+				return m, nil
+			}
+		}
+		return m, tea.Quit
+	case writeMsg:
+		if !m.inplace {
+			m.table.setError(fmt.Errorf("not editing a file in place, the table is printed on quit"))
+			return m, nil
+		}
+		if err := m.write(); err != nil {
+			m.table.setError(err)
+			return m, nil
+		}
+		m.table.setMessage(fmt.Sprintf("%q written", m.fpath))
+		return m, nil
+	case writeQuitMsg:
+		if err := m.write(); err != nil {
+			m.table.setError(err)
+			return m, nil
 		}
 		return m, tea.Quit
+	case discardMsg:
+		return m, tea.Quit
 	}
 
 	if m.preview {
@@ -47,12 +101,89 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+func (m Model) updateConfirmQuit(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch keyMsg.String() {
+	case "y", "Y":
+		if err := m.write(); err != nil {
+			m.confirmQuit = false
+			m.table.setError(err)
+			return m, nil
+		}
+		return m, tea.Quit
+	case "n", "N":
+		return m, tea.Quit
+	case "c", "C", "esc", "ctrl+c":
+		m.confirmQuit = false
+	}
+	return m, nil
+}
+
+// resize fits the tables and the table list to the terminal. The line below
+// the table is kept for the quit prompt.
+func (m *Model) resize(size tea.WindowSizeMsg) {
+	m.size = size
+	table := tea.WindowSizeMsg{Width: size.Width, Height: size.Height - 1}
+	for i := range m.tables {
+		m.tables[i], _ = m.tables[i].Update(table)
+	}
+	m.table, _ = m.table.Update(table)
+	if m.preview {
+		m.list, _ = m.list.update(size)
+	}
+}
+
+// write outputs the tables. Unchanged tables are not written back to the
+// file, while the current table is still printed to stdout so that piping
+// keeps working. The tables are kept modified when writing fails.
+func (m *Model) write() error {
+	m.syncTable()
+	if m.inplace && !m.modified() {
+		return nil
+	}
+	if err := Write(*m); err != nil {
+		return err
+	}
+	for i := range m.tables {
+		m.tables[i].markSaved()
+	}
+	m.table.markSaved()
+	return nil
+}
+
+// syncTable stores the table being edited back into the list of tables.
+func (m *Model) syncTable() {
+	if !m.preview && m.choose < len(m.tables) {
+		m.tables[m.choose] = m.table
+	}
+}
+
+// modified reports whether any table has unsaved changes.
+func (m Model) modified() bool {
+	for _, t := range m.tables {
+		if t.modified() {
+			return true
+		}
+	}
+	return false
+}
+
 func (m Model) View() string {
+	var s string
 	if m.preview {
-		return m.list.view()
+		s = m.list.view()
 	} else {
-		return m.table.View() + "\n"
+		s = m.table.View() + "\n"
+	}
+
+	if m.confirmQuit {
+		s += promptStyle.Render("Save changes before quitting? [y]es [n]o [c]ancel") + "\n"
 	}
+	return s
 }
 
 func NewUI(opts ...Option) (Model, error) {
@@ -63,7 +194,7 @@ func NewUI(opts ...Option) (Model, error) {
 		WithHeight(defaultHeight),
 		WithStyles(defaultStyles()),
 	)
-	m := Model{table: t}
+	m := Model{table: t, tables: []TableModel{t}, keys: defaultKeyMap()}
 
 	for _, opt := range opts {
 		err := opt(&m)
@@ -71,6 +202,13 @@ func NewUI(opts ...Option) (Model, error) {
 			return m, err
 		}
 	}
+
+	for i := range m.tables {
+		m.tables[i].setLocation(m.fpath, i, len(m.tables))
+		m.tables[i].keys = m.keys
+	}
+	m.table.setLocation(m.fpath, m.choose, len(m.tables))
+	m.table.keys = m.keys
 	return m, nil
 }
 
@@ -3,15 +3,205 @@ package mdtt
 import (
 	"strings"
 
+	"github.com/charmbracelet/lipgloss"
 	"github.com/mattn/go-runewidth"
+	"github.com/muesli/reflow/ansi"
+	"github.com/rivo/uniseg"
 )
 
+func init() {
+	// runewidth, which lipgloss uses, detects the width from the locale
+	SetAmbiguousWidth(runewidth.EastAsianWidth)
+}
+
+// displayWidth returns the number of terminal columns taken by s. A grapheme
+// cluster, such as an emoji sequence or a letter with combining marks, is
+// measured as a whole, and ANSI escape sequences take no space.
+func displayWidth(s string) int {
+	return uniseg.StringWidth(stripANSI(s))
+}
+
+// stripANSI removes the ANSI escape sequences of s.
+func stripANSI(s string) string {
+	if !strings.ContainsRune(s, ansi.Marker) {
+		return s
+	}
+
+	var sb strings.Builder
+	var esc bool
+	for _, c := range s {
+		switch {
+		case c == ansi.Marker:
+			esc = true
+		case esc:
+			esc = !ansi.IsTerminator(c)
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// SetAmbiguousWidth sets whether the characters of ambiguous East Asian
+// width, such as "○" and "α", take two columns instead of one, which should
+// match the terminal. By default they do in East Asian locales, or when
+// RUNEWIDTH_EASTASIAN is 1.
+func SetAmbiguousWidth(double bool) {
+	uniseg.EastAsianAmbiguousWidth = 1
+	if double {
+		uniseg.EastAsianAmbiguousWidth = 2
+	}
+	runewidth.EastAsianWidth = double
+	runewidth.DefaultCondition.EastAsianWidth = double
+}
+
+// truncate cuts s to at most n columns, keeping whole grapheme clusters and
+// the ANSI escape sequences of s. When s is cut, tail ends it within the n
+// columns.
+func truncate(s string, n int, tail string) string {
+	if displayWidth(s) <= n {
+		return s
+	}
+	n -= displayWidth(tail)
+
+	var sb strings.Builder
+	var w int
+	state := -1
+	for s != "" {
+		if s[0] == ansi.Marker {
+			end := escapeEnd(s)
+			sb.WriteString(s[:end])
+			s, state = s[end:], -1
+			continue
+		}
+		cluster, rest, cw, st := uniseg.FirstGraphemeClusterInString(s, state)
+		if w+cw > n {
+			break
+		}
+		sb.WriteString(cluster)
+		w += cw
+		s, state = rest, st
+	}
+	sb.WriteString(tail)
+
+	// the escape sequences left, such as resets, still apply
+	for s != "" {
+		if s[0] == ansi.Marker {
+			end := escapeEnd(s)
+			sb.WriteString(s[:end])
+			s = s[end:]
+		} else {
+			s = s[1:]
+		}
+	}
+	return sb.String()
+}
+
+// escapeEnd returns the length of the ANSI escape sequence s starts with.
+func escapeEnd(s string) int {
+	for i, c := range s[1:] {
+		if ansi.IsTerminator(c) {
+			return i + 2
+		}
+	}
+	return len(s)
+}
+
 func padOrTruncate(s string, n int) string {
-	if runewidth.StringWidth(s) > n {
-		return runewidth.Truncate(s, n, "")
-	} else {
-		return s + strings.Repeat(" ", n-runewidth.StringWidth(s))
+	s = truncate(s, n, "")
+	return s + strings.Repeat(" ", n-displayWidth(s))
+}
+
+// alignText pads s to the width n like padOrTruncate, placing it by the
+// alignment of its column. The last character is kept as a space to separate
+// the text from the next "|".
+func alignText(s string, n int, alignment string) string {
+	w := displayWidth(s)
+	if w >= n-1 {
+		return padOrTruncate(s, n)
+	}
+
+	pad := n - 1 - w
+	switch alignment {
+	case "right":
+		return strings.Repeat(" ", pad) + s + " "
+	case "center":
+		return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2) + " "
+	}
+	return padOrTruncate(s, n)
+}
+
+// nextAlignment returns the alignment that follows a when cycling through
+// none, left, center and right.
+func nextAlignment(a string) string {
+	switch a {
+	case "left":
+		return "center"
+	case "center":
+		return "right"
+	case "right":
+		return "none"
+	}
+	return "left"
+}
+
+// alignLine pads the line s of a cell to the width n by the alignment of its
+// column. Unlike alignText, it never truncates s, which may be styled.
+func alignLine(s string, n int, alignment string) string {
+	pad := max(n-displayWidth(s), 0)
+	switch alignment {
+	case "right":
+		return strings.Repeat(" ", pad) + s
+	case "center":
+		return strings.Repeat(" ", pad/2) + s + strings.Repeat(" ", pad-pad/2)
+	}
+	return s + strings.Repeat(" ", pad)
+}
+
+// drawFrame draws the border of style around the lines of s, measuring them
+// by displayWidth. The trailing spaces of the lines are dropped, as they may
+// come from lipgloss padding lines by its own measure.
+func drawFrame(style lipgloss.Style, s string) string {
+	lines := strings.Split(s, "\n")
+	var width int
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+		width = max(displayWidth(lines[i]), width)
+	}
+
+	b := style.GetBorderStyle()
+	fg := lipgloss.NewStyle().Foreground(style.GetBorderTopForeground())
+	var sb strings.Builder
+	sb.WriteString(fg.Render(b.TopLeft + strings.Repeat(b.Top, width) + b.TopRight))
+	for _, l := range lines {
+		sb.WriteString("\n" + fg.Render(b.Left) + alignLine(l, width, "left") + fg.Render(b.Right))
+	}
+	sb.WriteString("\n" + fg.Render(b.BottomLeft+strings.Repeat(b.Bottom, width)+b.BottomRight))
+	return sb.String()
+}
+
+// frameWidth returns the number of columns the border drawn by drawFrame
+// takes.
+func frameWidth(style lipgloss.Style) int {
+	b := style.GetBorderStyle()
+	return displayWidth(b.Left) + displayWidth(b.Right)
+}
+
+// alignmentIndicator renders the line below a header cell of width w, which
+// marks the alignment like the delimiter row of the markdown table.
+func alignmentIndicator(a string, w int) string {
+	if w < 2 {
+		return strings.Repeat("─", max(w, 0))
+	}
+	switch a {
+	case "left":
+		return ":" + strings.Repeat("─", w-1)
+	case "right":
+		return strings.Repeat("─", w-1) + ":"
+	case "center":
+		return ":" + strings.Repeat("─", max(w-2, 0)) + ":"
 	}
+	return strings.Repeat("─", w)
 }
 func max(a, b int) int {
 	if a > b {
@@ -0,0 +1,101 @@
+package mdtt
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mattn/go-runewidth"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	testCases := []struct {
+		name   string
+		s      string
+		double bool
+		want   int
+	}{
+		{name: "ascii", s: "abc", want: 3},
+		{name: "cjk", s: "日本語", want: 6},
+		{name: "halfwidth katakana", s: "ｶﾀｶﾅ", want: 4},
+		{name: "emoji", s: "👍", want: 2},
+		{name: "emoji modifier", s: "👍🏽", want: 2},
+		{name: "zwj sequence", s: "👨‍👩‍👧", want: 2},
+		{name: "flag", s: "🇯🇵", want: 2},
+		{name: "combining marks", s: "é", want: 1},
+		{name: "ansi", s: "\x1b[1;31mred\x1b[0m", want: 3},
+		{name: "ambiguous single", s: "○α", want: 2},
+		{name: "ambiguous double", s: "○α", double: true, want: 4},
+	}
+
+	ambiguous := runewidth.DefaultCondition.EastAsianWidth
+	t.Cleanup(func() { SetAmbiguousWidth(ambiguous) })
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			SetAmbiguousWidth(tc.double)
+			if got := displayWidth(tc.s); got != tc.want {
+				t.Errorf("want %d, got %d", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestViewWideCharacters(t *testing.T) {
+	src := "| 名前 | 絵文字 |\n| :-- | :-: |\n| 田中 | 👨‍👩‍👧 |\n| é | 🇯🇵<br>👍🏽 |\n"
+	m := parse([]byte(src))[0]
+	m.switchMode(NORMAL)
+
+	lines := strings.Split(frameView(m.View()), "\n")
+	for _, l := range lines {
+		if displayWidth(l) != displayWidth(lines[0]) {
+			t.Errorf("line %q is %d columns wide, the frame is %d", l, displayWidth(l), displayWidth(lines[0]))
+		}
+	}
+}
+
+func TestPadOrTruncate(t *testing.T) {
+	testCases := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{s: "abc", n: 5, want: "abc  "},
+		{s: "abcdef", n: 4, want: "abcd"},
+		{s: "日本語", n: 5, want: "日本 "},
+		{s: "a👨‍👩‍👧b", n: 3, want: "a👨‍👩‍👧"},
+		{s: "a👨‍👩‍👧b", n: 2, want: "a "},
+		{s: "éé", n: 1, want: "é"},
+	}
+
+	for _, tc := range testCases {
+		if got := padOrTruncate(tc.s, tc.n); got != tc.want {
+			t.Errorf("padOrTruncate(%q, %d): want %q, got %q", tc.s, tc.n, tc.want, got)
+		}
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	testCases := []struct {
+		s    string
+		n    int
+		want string
+	}{
+		{s: "abc", n: 3, want: "abc"},
+		{s: "abcdef", n: 4, want: "abc…"},
+		{s: "日本語", n: 4, want: "日…"},
+		{s: "🇯🇵🇯🇵", n: 3, want: "🇯🇵…"},
+		{s: "\x1b[1mbold\x1b[0m text", n: 5, want: "\x1b[1mbold\x1b[0m…"},
+		{s: "\x1b[1mbold text\x1b[0m", n: 3, want: "\x1b[1mbo…\x1b[0m"},
+	}
+
+	for _, tc := range testCases {
+		if got := truncate(tc.s, tc.n, "…"); got != tc.want {
+			t.Errorf("truncate(%q, %d): want %q, got %q", tc.s, tc.n, tc.want, got)
+		}
+	}
+}
+
+// frameView returns the lines of view down to the bottom of the table frame.
+func frameView(view string) string {
+	frame, _, _ := strings.Cut(view, "┘")
+	return frame + "┘"
+}
@@ -0,0 +1,415 @@
+package mdtt
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// selection is a visual selection between anchor and the cursor.
+type selection struct {
+	mode   int
+	anchor position
+	cursor position
+	// header is set when a column-wise selection is started from the header.
+	header bool
+}
+
+func isVisual(mode int) bool {
+	return mode == VISUAL || mode == VISUAL_LINE || mode == VISUAL_COLUMN
+}
+
+// startVisual enters the visual mode at the cursor, selecting n columns for a
+// column-wise selection and n rows otherwise. Only a column-wise selection can
+// be started from the header.
+func (m *TableModel) startVisual(mode, n int) {
+	header := m.mode == HEADER
+	if (header && mode != VISUAL_COLUMN) || len(m.cols) == 0 {
+		return
+	}
+	if !header && len(m.rows) == 0 {
+		return
+	}
+
+	m.visual = selection{
+		mode:   mode,
+		anchor: position{x: m.cursor.x, y: m.cursor.y},
+		header: header,
+	}
+	m.switchMode(mode)
+	if mode == VISUAL_COLUMN {
+		m.moveRight(n - 1)
+	} else {
+		m.moveVisual(n - 1)
+	}
+}
+
+// exitVisual leaves the visual mode and remembers the selection for the
+// '<,'> range of the command line.
+func (m *TableModel) exitVisual() {
+	m.visual.cursor = position{x: m.cursor.x, y: m.cursor.y}
+	m.lastVisual = m.visual
+	if m.visual.header {
+		m.switchMode(HEADER)
+	} else {
+		m.switchMode(NORMAL)
+	}
+}
+
+// rect returns the inclusive bounds of the selection. The cursor of an active
+// selection is given by cur.
+func (s selection) rect(cur position, rows, cols int) (x0, y0, x1, y1 int) {
+	x0, x1 = min(s.anchor.x, cur.x), max(s.anchor.x, cur.x)
+	y0, y1 = min(s.anchor.y, cur.y), max(s.anchor.y, cur.y)
+	switch s.mode {
+	case VISUAL_LINE:
+		x0, x1 = 0, cols-1
+	case VISUAL_COLUMN:
+		y0, y1 = 0, rows-1
+	}
+	return clamp(x0, 0, cols-1), clamp(y0, 0, rows-1),
+		clamp(x1, 0, cols-1), clamp(y1, 0, rows-1)
+}
+
+// selectionRect returns the inclusive bounds of the active selection.
+func (m TableModel) selectionRect() (x0, y0, x1, y1 int) {
+	return m.visual.rect(position{x: m.cursor.x, y: m.cursor.y}, len(m.rows), len(m.cols))
+}
+
+// isSelected reports whether the body cell at x, y is in the active selection.
+func (m TableModel) isSelected(x, y int) bool {
+	if !isVisual(m.mode) {
+		return false
+	}
+	x0, y0, x1, y1 := m.selectionRect()
+	return x0 <= x && x <= x1 && y0 <= y && y <= y1
+}
+
+// isColumnSelected reports whether the header of column x is in the active
+// selection.
+func (m TableModel) isColumnSelected(x int) bool {
+	if m.mode != VISUAL_COLUMN {
+		return false
+	}
+	x0, _, x1, _ := m.selectionRect()
+	return x0 <= x && x <= x1
+}
+
+// lastSelectionCells returns the body cells of the last visual selection.
+func (m TableModel) lastSelectionCells() []position {
+	if len(m.rows) == 0 || len(m.cols) == 0 {
+		return nil
+	}
+	x0, y0, x1, y1 := m.lastVisual.rect(m.lastVisual.cursor, len(m.rows), len(m.cols))
+	var cells []position
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			cells = append(cells, position{x: x, y: y})
+		}
+	}
+	return cells
+}
+
+func (m *TableModel) updateVisual(msg tea.KeyMsg) tea.Cmd {
+	if m.pending.addDigit(msg) {
+		return nil
+	}
+	if !m.pending.push(msg, m.keys.bindings(visualScope)) {
+		return nil
+	}
+	p, n := m.pending, m.pending.n()
+	m.pending.reset()
+
+	switch {
+	case p.is(m.keys.normalMode):
+		m.exitVisual()
+	case p.is(m.keys.visual, m.keys.visualLine, m.keys.visualBlock):
+		mode := VISUAL_COLUMN
+		if p.is(m.keys.visualLine) {
+			mode = VISUAL_LINE
+		} else if p.is(m.keys.visualBlock) {
+			mode = VISUAL
+		}
+		if mode == m.mode {
+			m.exitVisual()
+		} else if !m.visual.header {
+			m.visual.mode = mode
+			m.switchMode(mode)
+		}
+	case p.is(m.keys.lineUp):
+		m.moveVisual(-n)
+	case p.is(m.keys.lineDown):
+		m.moveVisual(n)
+	case p.is(m.keys.pageUp):
+		m.moveVisual(-m.viewport.Height * n)
+	case p.is(m.keys.pageDown):
+		m.moveVisual(m.viewport.Height * n)
+	case p.is(m.keys.halfPageUp):
+		m.moveVisual(-m.viewport.Height / 2 * n)
+	case p.is(m.keys.halfPageDown):
+		m.moveVisual(m.viewport.Height / 2 * n)
+	case p.is(m.keys.gotoTop):
+		m.moveVisual(-len(m.rows))
+	case p.is(m.keys.gotoBottom):
+		m.moveVisual(len(m.rows))
+	case p.is(m.keys.right):
+		m.moveRight(n)
+	case p.is(m.keys.left):
+		m.moveLeft(n)
+	case p.is(m.keys.align):
+		x0, _, x1, _ := m.selectionRect()
+		m.cycleAlignment(x0, x1)
+	case p.is(m.keys.moveRowUp):
+		m.moveRow(-n)
+	case p.is(m.keys.moveRowDown):
+		m.moveRow(n)
+	case p.is(m.keys.moveColLeft):
+		m.moveColumn(-n)
+	case p.is(m.keys.moveColRight):
+		m.moveColumn(n)
+	case p.is(m.keys.yank):
+		m.yankSelection()
+		m.exitVisual()
+		m.moveToSelectionStart()
+	case p.is(m.keys.delete):
+		m.deleteSelection()
+	case p.is(m.keys.clearCell):
+		m.fillSelection("")
+		m.exitVisual()
+	case p.is(m.keys.paste):
+		m.pasteSelection()
+		m.exitVisual()
+	case p.is(m.keys.fill):
+		m.fillDown()
+		m.exitVisual()
+	case p.is(m.keys.replace):
+		m.exitVisual()
+		m.openCommandLine()
+		m.cmdline.SetValue("'<,'>fill ")
+		m.cmdline.CursorEnd()
+		return textinput.Blink
+	case p.is(m.keys.command):
+		m.exitVisual()
+		m.openCommandLine()
+		m.cmdline.SetValue("'<,'>")
+		m.cmdline.CursorEnd()
+		return textinput.Blink
+	case p.is(m.keys.addRowCol):
+		if m.mode != VISUAL_COLUMN {
+			return nil
+		}
+		m.exitVisual()
+		m.saveSnapshot()
+		m.addColumn()
+		if m.mode == HEADER {
+			m.switchMode(HEADER_INSERT)
+		} else {
+			m.switchMode(INSERT)
+		}
+	}
+	return nil
+}
+
+// moveVisual moves the cursor of the selection by n rows. A column-wise
+// selection started from the header does not move vertically.
+func (m *TableModel) moveVisual(n int) {
+	switch {
+	case m.visual.header:
+	case n < 0:
+		m.moveUp(-n)
+	case n > 0:
+		m.moveDown(n)
+	}
+}
+
+// moveToSelectionStart moves the cursor to the top left cell of the last
+// selection.
+func (m *TableModel) moveToSelectionStart() {
+	x0, y0, _, _ := m.lastVisual.rect(m.lastVisual.cursor, len(m.rows), len(m.cols))
+	m.cursor.x = max(x0, 0)
+	if m.mode != HEADER {
+		m.SetCursor(y0)
+	}
+	m.updateViewport()
+}
+
+// yankSelection copies the selected rows, columns or cells to the register.
+func (m *TableModel) yankSelection() {
+	x0, y0, x1, y1 := m.selectionRect()
+
+	switch m.mode {
+	case VISUAL_LINE:
+		m.register = rowRegister{rows: copyRows(m.rows[y0 : y1+1])}
+		m.setMessage(fmt.Sprintf("%d rows yanked", y1-y0+1))
+	case VISUAL_COLUMN:
+		var reg colRegister
+		for x := x0; x <= x1; x++ {
+			reg.cols = append(reg.cols, copyColumns(m.cols[x:x+1])...)
+			var cells []cell
+			for _, r := range m.rows {
+				cells = append(cells, NewCell(r[x].value()))
+			}
+			reg.cells = append(reg.cells, cells)
+		}
+		m.register = reg
+		m.setMessage(fmt.Sprintf("%d columns yanked", x1-x0+1))
+	default:
+		var reg blockRegister
+		for y := y0; y <= y1; y++ {
+			reg.cells = append(reg.cells, cloneRow(m.rows[y][x0:x1+1]))
+		}
+		m.register = reg
+		m.setMessage(fmt.Sprintf("block of %d cells yanked", (y1-y0+1)*(x1-x0+1)))
+	}
+}
+
+// deleteSelection deletes the selected rows or columns, or clears the
+// selected cells of a block. The deleted contents are yanked. A table keeps
+// at least one column.
+func (m *TableModel) deleteSelection() {
+	x0, y0, x1, y1 := m.selectionRect()
+	mode := m.mode
+	if mode == VISUAL_COLUMN && x1-x0+1 >= len(m.cols) {
+		m.exitVisual()
+		m.setError(fmt.Errorf("cannot delete every column"))
+		return
+	}
+	m.yankSelection()
+	m.exitVisual()
+	m.saveSnapshot()
+
+	switch mode {
+	case VISUAL_LINE:
+		for y := y1; y >= y0; y-- {
+			m.deleteRow(y)
+		}
+		m.setMessage(fmt.Sprintf("%d rows deleted", y1-y0+1))
+		m.cursor.y = max(clamp(y0, 0, len(m.rows)-1), 0)
+		if len(m.rows) == 0 {
+			m.switchMode(HEADER)
+		}
+		m.SetHeight(len(m.rows))
+	case VISUAL_COLUMN:
+		for x := x1; x >= x0; x-- {
+			m.deleteColumn(x)
+		}
+		m.setMessage(fmt.Sprintf("%d columns deleted", x1-x0+1))
+		m.cursor.x = max(clamp(x0, 0, len(m.cols)-1), 0)
+	default:
+		for y := y0; y <= y1; y++ {
+			for x := x0; x <= x1; x++ {
+				m.rows[y][x].setValue("")
+			}
+		}
+		m.dropSnapshotIfUnchanged()
+		m.moveToSelectionStart()
+	}
+	m.updateViewport()
+}
+
+// fillSelection sets every selected cell to s. A column-wise selection of a
+// table without rows has no cells.
+func (m *TableModel) fillSelection(s string) {
+	if len(m.rows) == 0 {
+		return
+	}
+	x0, y0, x1, y1 := m.selectionRect()
+	m.saveSnapshot()
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			m.rows[y][x].setValue(s)
+		}
+	}
+	m.dropSnapshotIfUnchanged()
+	m.updateWidths()
+	m.updateViewport()
+}
+
+// fillDown copies the first selected cell of each column to the cells below.
+func (m *TableModel) fillDown() {
+	if len(m.rows) == 0 {
+		return
+	}
+	x0, y0, x1, y1 := m.selectionRect()
+	m.saveSnapshot()
+	for x := x0; x <= x1; x++ {
+		v := m.rows[y0][x].value()
+		for y := y0 + 1; y <= y1; y++ {
+			m.rows[y][x].setValue(v)
+		}
+	}
+	m.dropSnapshotIfUnchanged()
+	m.updateWidths()
+	m.updateViewport()
+}
+
+// pasteSelection repeats the contents of the register over the selection.
+func (m *TableModel) pasteSelection() {
+	values := m.registerValues()
+	if len(values) == 0 || len(values[0]) == 0 || len(m.rows) == 0 {
+		return
+	}
+
+	x0, y0, x1, y1 := m.selectionRect()
+	m.saveSnapshot()
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			r := values[(y-y0)%len(values)]
+			m.rows[y][x].setValue(r[(x-x0)%len(r)])
+		}
+	}
+	m.dropSnapshotIfUnchanged()
+	m.updateWidths()
+	m.updateViewport()
+}
+
+// registerValues returns the contents of the register as a grid of values.
+func (m TableModel) registerValues() [][]string {
+	var grid []row
+	switch reg := m.register.(type) {
+	case rowRegister:
+		grid = reg.rows
+	case colRegister:
+		if len(reg.cells) == 0 {
+			return nil
+		}
+		for y := range reg.cells[0] {
+			var r row
+			for x := range reg.cells {
+				r = append(r, reg.cells[x][y])
+			}
+			grid = append(grid, r)
+		}
+	case blockRegister:
+		grid = reg.cells
+	case cellRegister:
+		grid = []row{{reg.cell}}
+	}
+
+	values := make([][]string, len(grid))
+	for y, r := range grid {
+		for _, c := range r {
+			values[y] = append(values[y], c.value())
+		}
+	}
+	return values
+}
+
+func cmdFill(m *TableModel, c exCommand) (tea.Cmd, error) {
+	cells := []position{{x: m.cursor.x, y: m.cursor.y}}
+	if c.rng == "'<,'>" {
+		cells = m.lastSelectionCells()
+	} else if len(m.rows) == 0 || m.mode == HEADER {
+		return nil, fmt.Errorf("E16: Invalid range")
+	}
+
+	m.saveSnapshot()
+	for _, p := range cells {
+		m.rows[p.y][p.x].setValue(c.args)
+	}
+	m.dropSnapshotIfUnchanged()
+	m.updateWidths()
+	m.updateViewport()
+	return nil, nil
+}
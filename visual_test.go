@@ -0,0 +1,274 @@
+package mdtt
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestVisual(t *testing.T) {
+	newTable := func() TableModel {
+		return NewTableModel(
+			WithColumns([]column{
+				{title: NewCell("a"), width: 5, alignment: "left"},
+				{title: NewCell("b"), width: 5, alignment: "right"},
+				{title: NewCell("c"), width: 5},
+			}),
+			WithNaiveRows([]naiveRow{
+				{"1", "2", "3"},
+				{"4", "5", "6"},
+				{"7", "8", "9"},
+			}),
+		)
+	}
+
+	testCases := []struct {
+		name  string
+		keys  []tea.KeyMsg
+		want  [][]string
+		title []string
+	}{
+		{
+			name:  "delete rows",
+			keys:  keys("jVkd"),
+			want:  [][]string{{"7", "8", "9"}},
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name:  "delete columns",
+			keys:  keys("vld"),
+			want:  [][]string{{"3"}, {"6"}, {"9"}},
+			title: []string{"c"},
+		},
+		{
+			name:  "delete columns from header",
+			keys:  append(keys("k"), keys("vld")...),
+			want:  [][]string{{"3"}, {"6"}, {"9"}},
+			title: []string{"c"},
+		},
+		{
+			name: "clear block",
+			keys: append([]tea.KeyMsg{{Type: tea.KeyCtrlV}}, keys("ljx")...),
+			want: [][]string{
+				{"", "", "3"},
+				{"", "", "6"},
+				{"7", "8", "9"},
+			},
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name: "yank and paste block",
+			keys: append(append([]tea.KeyMsg{{Type: tea.KeyCtrlV}}, keys("ly")...),
+				keys("jjp")...),
+			want: [][]string{
+				{"1", "2", "3"},
+				{"4", "5", "6"},
+				{"1", "2", "9"},
+			},
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name: "paste over selection",
+			keys: append(append(keys("y."), tea.KeyMsg{Type: tea.KeyCtrlV}), keys("jjlp")...),
+			want: [][]string{
+				{"1", "1", "3"},
+				{"1", "1", "6"},
+				{"1", "1", "9"},
+			},
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name: "fill down",
+			keys: keys("VjjF"),
+			want: [][]string{
+				{"1", "2", "3"},
+				{"1", "2", "3"},
+				{"1", "2", "3"},
+			},
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name: "yank and paste columns",
+			keys: keys("vlyllp"),
+			want: [][]string{
+				{"1", "2", "3", "1", "2"},
+				{"4", "5", "6", "4", "5"},
+				{"7", "8", "9", "7", "8"},
+			},
+			title: []string{"a", "b", "c", "a", "b"},
+		},
+		{
+			name: "yank and paste rows",
+			keys: keys("Vjyjjp"),
+			want: [][]string{
+				{"1", "2", "3"},
+				{"4", "5", "6"},
+				{"7", "8", "9"},
+				{"1", "2", "3"},
+				{"4", "5", "6"},
+			},
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name: "fill selection",
+			keys: append(append(keys("jV"), keys("r")...), append(keys("x"), tea.KeyMsg{Type: tea.KeyEnter})...),
+			want: [][]string{
+				{"1", "2", "3"},
+				{"x", "x", "x"},
+				{"7", "8", "9"},
+			},
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name:  "delete every column",
+			keys:  keys("3vdhx"),
+			want:  [][]string{{"1", "", "3"}, {"4", "5", "6"}, {"7", "8", "9"}},
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name:  "delete every column from header",
+			keys:  keys("k3vdhx"),
+			want:  [][]string{{"1", "2", "3"}, {"4", "5", "6"}, {"7", "8", "9"}},
+			title: []string{"a", "", "c"},
+		},
+		{
+			name:  "clear columns of a table without rows",
+			keys:  keys("VGdvx"),
+			want:  nil,
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name:  "fill down columns of a table without rows",
+			keys:  keys("VGdvF"),
+			want:  nil,
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name:  "add a column after reselecting in a table without rows",
+			keys:  append(keys("VGdgvoz"), tea.KeyMsg{Type: tea.KeyEsc}),
+			want:  nil,
+			title: []string{"a", "z", "b", "c"},
+		},
+		{
+			name:  "paste over columns of a table without rows",
+			keys:  keys("VGdvp"),
+			want:  nil,
+			title: []string{"a", "b", "c"},
+		},
+		{
+			name: "substitute in selection",
+			keys: append(append([]tea.KeyMsg{{Type: tea.KeyCtrlV}}, keys("jl:s/./x/")...),
+				tea.KeyMsg{Type: tea.KeyEnter}),
+			want: [][]string{
+				{"x", "x", "3"},
+				{"x", "x", "6"},
+				{"7", "8", "9"},
+			},
+			title: []string{"a", "b", "c"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTable()
+			for _, k := range tc.keys {
+				m, _ = m.Update(k)
+			}
+			if isVisual(m.mode) {
+				t.Errorf("still in visual mode %d", m.mode)
+			}
+			if diff := cmp.Diff(tc.want, values(m.rows)); diff != "" {
+				t.Errorf("rows differ: (-want +got)\n%s", diff)
+			}
+			var title []string
+			for _, c := range m.cols {
+				title = append(title, c.title.value())
+			}
+			if diff := cmp.Diff(tc.title, title); diff != "" {
+				t.Errorf("titles differ: (-want +got)\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestVisualPasteColumnKeepsAlignment(t *testing.T) {
+	m := NewTableModel(
+		WithColumns([]column{
+			{title: NewCell("a"), width: 5, alignment: "right"},
+			{title: NewCell("b"), width: 5, alignment: "center"},
+		}),
+		WithNaiveRows([]naiveRow{{"1", "2"}}),
+	)
+	for _, k := range keys("vdp") {
+		m, _ = m.Update(k)
+	}
+
+	var got []string
+	for _, c := range m.cols {
+		got = append(got, c.alignment)
+	}
+	if diff := cmp.Diff([]string{"center", "right"}, got); diff != "" {
+		t.Errorf("alignments differ: (-want +got)\n%s", diff)
+	}
+}
+
+func TestPasteRowsFitColumns(t *testing.T) {
+	testCases := []struct {
+		name string
+		keys []tea.KeyMsg
+		want [][]string
+	}{
+		{
+			name: "after adding a column",
+			keys: append(append(keys("Vdvo"), tea.KeyMsg{Type: tea.KeyEsc}), keys("p")...),
+			want: [][]string{{"4", "", "5", "6"}, {"1", "2", "3", ""}, {"7", "", "8", "9"}},
+		},
+		{
+			name: "after undoing an added column",
+			keys: append(append(keys("vo"), tea.KeyMsg{Type: tea.KeyEsc}), keys("yyup")...),
+			want: [][]string{{"1", "2", "3"}, {"1", "", "2"}, {"4", "5", "6"}, {"7", "8", "9"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := NewTableModel(
+				WithColumns([]column{
+					{title: NewCell("a"), width: 5},
+					{title: NewCell("b"), width: 5},
+					{title: NewCell("c"), width: 5},
+				}),
+				WithNaiveRows([]naiveRow{{"1", "2", "3"}, {"4", "5", "6"}, {"7", "8", "9"}}),
+			)
+			for _, k := range tc.keys {
+				m, _ = m.Update(k)
+			}
+			if diff := cmp.Diff(tc.want, values(m.rows)); diff != "" {
+				t.Errorf("rows differ: (-want +got)\n%s", diff)
+			}
+			// the pasted rows are drawn with every column
+			m.View()
+		})
+	}
+}
+
+func keys(s string) []tea.KeyMsg {
+	var msgs []tea.KeyMsg
+	for _, r := range s {
+		msgs = append(msgs, tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+	}
+	return msgs
+}
+
+func values(rows []row) [][]string {
+	var v [][]string
+	for _, r := range rows {
+		var vr []string
+		for _, c := range r {
+			vr = append(vr, c.value())
+		}
+		v = append(v, vr)
+	}
+	return v
+}